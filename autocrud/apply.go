@@ -19,31 +19,45 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-func serverSideApply(ctx context.Context, clientGetter KubernetesClientGetter, apiVersion, kind string, model any) error {
+// ExpandManifest expands the model into the manifest for the resource
+func ExpandManifest(apiVersion, kind string, model any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetUnstructuredContent(ExpandModel(model))
+	obj.SetKind(kind)
+	obj.SetAPIVersion(apiVersion)
+	return obj
+}
+
+// ApplyManifest applies the manifest using server-side apply, flattens the
+// response into the model and returns the manifest from the response
+func ApplyManifest(ctx context.Context, clientGetter KubernetesClientGetter, obj *unstructured.Unstructured, model any) (*unstructured.Unstructured, error) {
+	apiVersion := obj.GetAPIVersion()
+	kind := obj.GetKind()
+
 	client, err := clientGetter.DynamicClient()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	discoveryClient, err := clientGetter.DiscoveryClient()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	agr, err := restmapper.GetAPIGroupResources(discoveryClient)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	gv, err := k8sschema.ParseGroupVersion(apiVersion)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	restMapper := restmapper.NewDiscoveryRESTMapper(agr)
 	mapping, err := restMapper.RESTMapping(gv.WithKind(kind).GroupKind(), gv.Version)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	manifest := ExpandModel(model)
+	manifest := obj.UnstructuredContent()
 
 	var resourceInterface dynamic.ResourceInterface
 	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
@@ -57,13 +71,9 @@ func serverSideApply(ctx context.Context, clientGetter KubernetesClientGetter, a
 		resourceInterface = client.Resource(mapping.Resource)
 	}
 
-	obj := unstructured.Unstructured{}
-	obj.SetUnstructuredContent(manifest)
-	obj.SetKind(kind)
-	obj.SetAPIVersion(apiVersion)
 	payload, err := obj.MarshalJSON()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	tflog.Debug(ctx, "Executing server-side apply operation", map[string]any{
@@ -76,7 +86,7 @@ func serverSideApply(ctx context.Context, clientGetter KubernetesClientGetter, a
 		},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	tflog.Debug(ctx, "Server-side apply operation succeeded", map[string]any{
@@ -93,11 +103,11 @@ func serverSideApply(ctx context.Context, clientGetter KubernetesClientGetter, a
 
 	err = FlattenManifest(responseManifest, model)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	setID(id, model)
 
-	return nil
+	return res, nil
 }
 
 func setID(ID string, model any) {
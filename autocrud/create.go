@@ -8,5 +8,6 @@ import (
 )
 
 func Create(ctx context.Context, clientGetter KubernetesClientGetter, apiVersion, kind string, model any) error {
-	return serverSideApply(ctx, clientGetter, apiVersion, kind, model)
+	_, err := ApplyManifest(ctx, clientGetter, ExpandManifest(apiVersion, kind, model), model)
+	return err
 }
@@ -119,3 +119,16 @@ func TestModelExpander(t *testing.T) {
 
 	assert.Equal(t, expectedResult, result)
 }
+
+func TestExpandManifest(t *testing.T) {
+	model := TestModel{}
+	model.Metadata.Name = types.StringValue("test")
+	model.Metadata.Namespace = types.StringValue("default")
+
+	manifest := ExpandManifest("v1", "ConfigMap", &model)
+
+	assert.Equal(t, "v1", manifest.GetAPIVersion())
+	assert.Equal(t, "ConfigMap", manifest.GetKind())
+	assert.Equal(t, "test", manifest.GetName())
+	assert.Equal(t, "default", manifest.GetNamespace())
+}
@@ -0,0 +1,99 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package autocrud
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The hook interfaces are implemented by resources that need to run code
+// before or after an autocrud operation. M is the model type of the resource.
+// The generated CRUD functions check for each hook with a type assertion and
+// stop the operation if the hook adds an error to the response diagnostics.
+//
+// The manifest passed to the before create and update hooks is the one that
+// will be applied, so it can be changed to alter what is sent to the API
+// server. Changes the before create and update hooks make to the model are
+// applied too, see ExpandManifestAfterHook. The after create, read and update
+// hooks get the manifest returned by the API server. The other hooks get the
+// manifest expanded from the model.
+
+// BeforeCreateHook is called before the resource is created
+type BeforeCreateHook[M any] interface {
+	BeforeCreate(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, model *M, manifest *unstructured.Unstructured)
+}
+
+// AfterCreateHook is called after the resource is created
+type AfterCreateHook[M any] interface {
+	AfterCreate(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, model *M, manifest *unstructured.Unstructured)
+}
+
+// BeforeReadHook is called before the resource is read
+type BeforeReadHook[M any] interface {
+	BeforeRead(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, model *M, manifest *unstructured.Unstructured)
+}
+
+// AfterReadHook is called after the resource is read
+type AfterReadHook[M any] interface {
+	AfterRead(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, model *M, manifest *unstructured.Unstructured)
+}
+
+// BeforeUpdateHook is called before the resource is updated
+type BeforeUpdateHook[M any] interface {
+	BeforeUpdate(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, model *M, manifest *unstructured.Unstructured)
+}
+
+// AfterUpdateHook is called after the resource is updated
+type AfterUpdateHook[M any] interface {
+	AfterUpdate(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, model *M, manifest *unstructured.Unstructured)
+}
+
+// BeforeDeleteHook is called before the resource is deleted
+type BeforeDeleteHook[M any] interface {
+	BeforeDelete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse, model *M, manifest *unstructured.Unstructured)
+}
+
+// AfterDeleteHook is called after the resource is deleted
+type AfterDeleteHook[M any] interface {
+	AfterDelete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse, model *M, manifest *unstructured.Unstructured)
+}
+
+// ExpandManifestAfterHook returns the manifest to apply after a before hook
+// has run. The manifest is expanded again from the model, so changes the hook
+// made to the model are applied, and then the changes the hook made to the
+// manifest, compared to the expanded manifest it was given, are applied on top.
+func ExpandManifestAfterHook(apiVersion, kind string, model any, expanded, manifest *unstructured.Unstructured) *unstructured.Unstructured {
+	obj := ExpandManifest(apiVersion, kind, model)
+	obj.SetUnstructuredContent(mergeManifestChanges(expanded.UnstructuredContent(), manifest.UnstructuredContent(), obj.UnstructuredContent()))
+	return obj
+}
+
+// mergeManifestChanges applies the fields that differ between original and
+// changed to target. Lists are replaced as a whole.
+func mergeManifestChanges(original, changed, target map[string]any) map[string]any {
+	for k, v := range changed {
+		ov, ok := original[k]
+		if ok && reflect.DeepEqual(ov, v) {
+			continue
+		}
+		om, omok := ov.(map[string]any)
+		vm, vmok := v.(map[string]any)
+		tm, tmok := target[k].(map[string]any)
+		if omok && vmok && tmok {
+			target[k] = mergeManifestChanges(om, vm, tm)
+			continue
+		}
+		target[k] = v
+	}
+	for k := range original {
+		if _, ok := changed[k]; !ok {
+			delete(target, k)
+		}
+	}
+	return target
+}
@@ -0,0 +1,62 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package autocrud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type hooksTestModel struct {
+	Metadata struct {
+		Name   types.String            `tfsdk:"name" manifest:"name"`
+		Labels map[string]types.String `tfsdk:"labels" manifest:"labels"`
+	} `tfsdk:"metadata" manifest:"metadata"`
+	Spec struct {
+		Replicas types.Int64  `tfsdk:"replicas" manifest:"replicas"`
+		Image    types.String `tfsdk:"image" manifest:"image"`
+	} `tfsdk:"spec" manifest:"spec"`
+}
+
+type hooksTestResource struct{}
+
+var _ BeforeCreateHook[hooksTestModel] = &hooksTestResource{}
+
+func (r *hooksTestResource) BeforeCreate(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, model *hooksTestModel, manifest *unstructured.Unstructured) {
+	model.Spec.Image = types.StringValue("nginx:latest")
+	manifest.SetLabels(map[string]string{"managed-by": "hook"})
+	unstructured.RemoveNestedField(manifest.Object, "spec", "replicas")
+}
+
+func TestExpandManifestAfterHook(t *testing.T) {
+	var model hooksTestModel
+	model.Metadata.Name = types.StringValue("test")
+	model.Spec.Replicas = types.Int64Value(3)
+
+	// this is what the generated Create function does
+	var r any = &hooksTestResource{}
+	manifest := ExpandManifest("example.com/v1", "Widget", &model)
+	if hook, ok := r.(BeforeCreateHook[hooksTestModel]); ok {
+		expanded := manifest.DeepCopy()
+		hook.BeforeCreate(context.Background(), resource.CreateRequest{}, &resource.CreateResponse{}, &model, manifest)
+		manifest = ExpandManifestAfterHook("example.com/v1", "Widget", &model, expanded, manifest)
+	}
+
+	assert.Equal(t, map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata": map[string]any{
+			"name":   "test",
+			"labels": map[string]any{"managed-by": "hook"},
+		},
+		"spec": map[string]any{
+			"image": "nginx:latest",
+		},
+	}, removeNulls(manifest.UnstructuredContent()))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/restmapper"
@@ -18,26 +19,33 @@ type StateGetter interface {
 }
 
 func Read(ctx context.Context, clientGetter KubernetesClientGetter, kind, apiVersion, id string, model any) error {
+	_, err := ReadManifest(ctx, clientGetter, kind, apiVersion, id, model)
+	return err
+}
+
+// ReadManifest reads the resource, flattens it into the model and returns
+// the manifest from the response
+func ReadManifest(ctx context.Context, clientGetter KubernetesClientGetter, kind, apiVersion, id string, model any) (*unstructured.Unstructured, error) {
 	client, err := clientGetter.DynamicClient()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	discoveryClient, err := clientGetter.DiscoveryClient()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	agr, err := restmapper.GetAPIGroupResources(discoveryClient)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	gv, err := k8sschema.ParseGroupVersion(apiVersion)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	restMapper := restmapper.NewDiscoveryRESTMapper(agr)
 	mapping, err := restMapper.RESTMapping(gv.WithKind(kind).GroupKind(), gv.Version)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	namespace, name := parseID(id)
@@ -58,7 +66,7 @@ func Read(ctx context.Context, clientGetter KubernetesClientGetter, kind, apiVer
 	})
 	res, err := resourceInterface.Get(ctx, name, v1.GetOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	tflog.Debug(ctx, "Resource read successfully", map[string]any{
 		"response": res,
@@ -81,5 +89,5 @@ func Read(ctx context.Context, clientGetter KubernetesClientGetter, kind, apiVer
 
 	FlattenManifest(responseManifest, model)
 	setID(id, model)
-	return nil
+	return res, nil
 }
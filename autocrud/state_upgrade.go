@@ -0,0 +1,207 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package autocrud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// AttributeMove describes an attribute whose path has changed between
+// schema versions. Paths use the same syntax as the generator configuration,
+// e.g. spec.template.spec.containers[*].ports
+type AttributeMove struct {
+	From string
+	To   string
+
+	// Key converts a list of objects into a map keyed by the value
+	// of this attribute in each object
+	Key string
+
+	// Unwrap converts a list containing a single object into that object,
+	// this is how SDKv2 represented nested blocks with MaxItems: 1
+	Unwrap bool
+}
+
+// UpgradeState reshapes raw state JSON from a prior schema version into a
+// value of the current state type, applying the supplied attribute moves.
+// Attributes that no longer exist in the state type are dropped and new
+// attributes are set to null.
+func UpgradeState(ctx context.Context, rawState *tfprotov6.RawState, stateType tftypes.Type, moves []AttributeMove) (tftypes.Value, error) {
+	return reshapeState(ctx, rawState, stateType, moves)
+}
+
+// MoveState reshapes the raw state JSON of a different resource type,
+// such as the SDKv2 implementation of a resource, into a value of the
+// current state type, applying the supplied attribute moves.
+func MoveState(ctx context.Context, sourceRawState *tfprotov6.RawState, stateType tftypes.Type, moves []AttributeMove) (tftypes.Value, error) {
+	return reshapeState(ctx, sourceRawState, stateType, moves)
+}
+
+func reshapeState(ctx context.Context, rawState *tfprotov6.RawState, stateType tftypes.Type, moves []AttributeMove) (tftypes.Value, error) {
+	if rawState == nil || rawState.JSON == nil {
+		return tftypes.Value{}, fmt.Errorf("state is not available as JSON")
+	}
+
+	state := map[string]any{}
+	dec := json.NewDecoder(bytes.NewReader(rawState.JSON))
+	// preserve the precision of int64 values
+	dec.UseNumber()
+	if err := dec.Decode(&state); err != nil {
+		return tftypes.Value{}, fmt.Errorf("error decoding state: %v", err)
+	}
+
+	for _, m := range moves {
+		tflog.Debug(ctx, "Moving attribute in state", map[string]any{
+			"from": m.From,
+			"to":   m.To,
+		})
+		err := moveAttribute(state, strings.Split(m.From, "."), strings.Split(m.To, "."), m)
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("error moving %q to %q: %v", m.From, m.To, err)
+		}
+	}
+
+	upgradedJSON, err := json.Marshal(state)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	upgraded := tfprotov6.RawState{JSON: upgradedJSON}
+	return upgraded.UnmarshalWithOpts(stateType, tfprotov6.UnmarshalOpts{
+		ValueFromJSONOpts: tftypes.ValueFromJSONOpts{
+			IgnoreUndefinedAttributes: true,
+		},
+	})
+}
+
+// moveAttribute walks the path segments that from and to have in common,
+// including into each element of a list, then moves the value at the
+// remainder of the from path to the remainder of the to path.
+func moveAttribute(obj map[string]any, from, to []string, move AttributeMove) error {
+	if len(from) > 1 && len(to) > 1 && from[0] == to[0] {
+		name, isList := strings.CutSuffix(from[0], "[*]")
+		v := obj[name]
+		if v == nil {
+			return nil
+		}
+		if !isList {
+			m, ok := v.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%q is not an object", name)
+			}
+			return moveAttribute(m, from[1:], to[1:], move)
+		}
+		l, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%q is not a list", name)
+		}
+		for _, e := range l {
+			m, ok := e.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%q is not a list of objects", name)
+			}
+			if err := moveAttribute(m, from[1:], to[1:], move); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	v, err := popValue(obj, from)
+	if err != nil || v == nil {
+		return err
+	}
+	if move.Unwrap {
+		v, err = unwrapList(v)
+		if err != nil || v == nil {
+			return err
+		}
+	}
+	if move.Key != "" {
+		v, err = keyList(v, move.Key)
+		if err != nil {
+			return err
+		}
+	}
+	return setValue(obj, to, v)
+}
+
+func popValue(obj map[string]any, path []string) (any, error) {
+	for i, p := range path {
+		if strings.HasSuffix(p, "[*]") {
+			return nil, fmt.Errorf("cannot move out of list %q", p)
+		}
+		if i == len(path)-1 {
+			v := obj[p]
+			delete(obj, p)
+			return v, nil
+		}
+		next, ok := obj[p].(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		obj = next
+	}
+	return nil, nil
+}
+
+func setValue(obj map[string]any, path []string, v any) error {
+	for i, p := range path {
+		if strings.HasSuffix(p, "[*]") {
+			return fmt.Errorf("cannot move into list %q", p)
+		}
+		if i == len(path)-1 {
+			obj[p] = v
+			return nil
+		}
+		next, ok := obj[p].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			obj[p] = next
+		}
+		obj = next
+	}
+	return nil
+}
+
+func keyList(v any, key string) (map[string]any, error) {
+	l, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot key a value that is not a list")
+	}
+	m := map[string]any{}
+	for _, e := range l {
+		obj, ok := e.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot key a list that does not contain objects")
+		}
+		k, ok := obj[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("list element has no string attribute %q", key)
+		}
+		m[k] = obj
+	}
+	return m, nil
+}
+
+func unwrapList(v any) (any, error) {
+	l, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot unwrap a value that is not a list")
+	}
+	switch len(l) {
+	case 0:
+		return nil, nil
+	case 1:
+		return l[0], nil
+	}
+	return nil, fmt.Errorf("cannot unwrap a list with %d elements", len(l))
+}
@@ -0,0 +1,135 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package autocrud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpgradeState(t *testing.T) {
+	portType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name": tftypes.String,
+		"port": tftypes.Number,
+	}}
+	stateType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id": tftypes.String,
+		"metadata": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"name":       tftypes.String,
+			"label_map":  tftypes.Map{ElementType: tftypes.String},
+			"generation": tftypes.Number,
+		}},
+		"ports": tftypes.Map{ElementType: portType},
+	}}
+
+	rawState := &tfprotov6.RawState{JSON: []byte(`{
+		"id": "default/test",
+		"metadata": {
+			"name": "test",
+			"labels": {"app": "test"},
+			"generation": 9223372036854775807,
+			"removed": "value"
+		},
+		"ports": [
+			{"name": "http", "port": 80},
+			{"name": "https", "port": 443}
+		]
+	}`)}
+	moves := []AttributeMove{
+		{From: "metadata.labels", To: "metadata.label_map"},
+		{From: "ports", To: "ports", Key: "name"},
+	}
+
+	actual, err := UpgradeState(context.Background(), rawState, stateType, moves)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := tftypes.NewValue(stateType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "default/test"),
+		"metadata": tftypes.NewValue(stateType.AttributeTypes["metadata"], map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "test"),
+			"label_map": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+				"app": tftypes.NewValue(tftypes.String, "test"),
+			}),
+			"generation": tftypes.NewValue(tftypes.Number, int64(9223372036854775807)),
+		}),
+		"ports": tftypes.NewValue(tftypes.Map{ElementType: portType}, map[string]tftypes.Value{
+			"http": tftypes.NewValue(portType, map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "http"),
+				"port": tftypes.NewValue(tftypes.Number, 80),
+			}),
+			"https": tftypes.NewValue(portType, map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "https"),
+				"port": tftypes.NewValue(tftypes.Number, 443),
+			}),
+		}),
+	})
+
+	assert.True(t, expected.Equal(actual), "expected %s got %s", expected, actual)
+}
+
+func TestMoveAttributeInList(t *testing.T) {
+	state := map[string]any{
+		"containers": []any{
+			map[string]any{"image_name": "nginx"},
+			map[string]any{"image_name": "redis"},
+		},
+	}
+
+	err := moveAttribute(state, []string{"containers[*]", "image_name"}, []string{"containers[*]", "image"}, AttributeMove{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{
+		"containers": []any{
+			map[string]any{"image": "nginx"},
+			map[string]any{"image": "redis"},
+		},
+	}
+	assert.Equal(t, expected, state)
+}
+
+func TestMoveStateFromSDKv2(t *testing.T) {
+	stateType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id": tftypes.String,
+		"metadata": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"name":      tftypes.String,
+			"namespace": tftypes.String,
+		}},
+		"data": tftypes.Map{ElementType: tftypes.String},
+	}}
+
+	sourceRawState := &tfprotov6.RawState{JSON: []byte(`{
+		"id": "default/test",
+		"metadata": [{"name": "test", "namespace": "default", "generate_name": ""}],
+		"data": {"key": "value"}
+	}`)}
+	moves := []AttributeMove{
+		{From: "metadata", To: "metadata", Unwrap: true},
+	}
+
+	actual, err := MoveState(context.Background(), sourceRawState, stateType, moves)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := tftypes.NewValue(stateType, map[string]tftypes.Value{
+		"id": tftypes.NewValue(tftypes.String, "default/test"),
+		"metadata": tftypes.NewValue(stateType.AttributeTypes["metadata"], map[string]tftypes.Value{
+			"name":      tftypes.NewValue(tftypes.String, "test"),
+			"namespace": tftypes.NewValue(tftypes.String, "default"),
+		}),
+		"data": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"key": tftypes.NewValue(tftypes.String, "value"),
+		}),
+	})
+
+	assert.True(t, expected.Equal(actual), "expected %s got %s", expected, actual)
+}
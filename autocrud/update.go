@@ -8,5 +8,6 @@ import (
 )
 
 func Update(ctx context.Context, clientGetter KubernetesClientGetter, kind, apiVersion string, model any) error {
-	return serverSideApply(ctx, clientGetter, apiVersion, kind, model)
+	_, err := ApplyManifest(ctx, clientGetter, ExpandManifest(apiVersion, kind, model), model)
+	return err
 }
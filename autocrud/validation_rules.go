@@ -0,0 +1,330 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package autocrud
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// validationRuleCostLimit limits how expensive a rule can be to evaluate
+const validationRuleCostLimit = 1000000
+
+// ValidationRule is a CEL rule from the x-kubernetes-validations extension
+// of a CRD schema
+type ValidationRule struct {
+	// AttributePath is the path of the attribute the rule applies to, e.g.
+	// spec.ports[*], it is empty if the rule applies to the whole resource
+	AttributePath string
+
+	// ManifestPath is the path of the same field in the manifest
+	ManifestPath string
+
+	// Rule is the CEL expression, self is the value of the field
+	Rule string
+
+	// Message is reported when the rule evaluates to false
+	Message string
+}
+
+// ValidationRulesValidator is a resource.ConfigValidator that evaluates
+// validation rules against the manifest expanded from the configuration, so
+// errors are reported when planning rather than by the API server. Rules are
+// skipped for values that are null or unknown.
+type ValidationRulesValidator[M any] struct {
+	APIVersion string
+	Kind       string
+	Rules      []ValidationRule
+}
+
+var _ resource.ConfigValidator = ValidationRulesValidator[struct{}]{}
+
+func (v ValidationRulesValidator[M]) Description(ctx context.Context) string {
+	return "Evaluates the x-kubernetes-validations rules of the resource"
+}
+
+func (v ValidationRulesValidator[M]) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ValidationRulesValidator[M]) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	// the manifest is built from the raw configuration rather than the
+	// model, as unknown objects and lists can't be read into the model
+	manifest, _ := manifestValue(reflect.TypeOf((*M)(nil)).Elem(), req.Config.Raw).(map[string]any)
+	if manifest == nil {
+		return
+	}
+	manifest["apiVersion"] = v.APIVersion
+	manifest["kind"] = v.Kind
+
+	for _, rule := range v.Rules {
+		prg, err := compileValidationRule(rule.Rule)
+		if err != nil {
+			tflog.Warn(ctx, "Skipping validation rule that could not be compiled", map[string]any{
+				"rule":  rule.Rule,
+				"error": err.Error(),
+			})
+			continue
+		}
+		evaluateValidationRule(ctx, req.Config, rule, prg, req.Config.Raw, manifest,
+			splitPath(rule.AttributePath), splitPath(rule.ManifestPath), path.Empty(), &resp.Diagnostics)
+	}
+}
+
+// evaluateValidationRule walks the configuration and the manifest to the
+// field the rule applies to and evaluates it for each value
+func evaluateValidationRule(ctx context.Context, c tfsdk.Config, rule ValidationRule, prg cel.Program, config tftypes.Value, manifest any, attributePath, manifestPath []string, p path.Path, diags *diag.Diagnostics) {
+	if manifest == nil || config.IsNull() || !config.IsKnown() {
+		return
+	}
+	if len(attributePath) == 0 {
+		if !config.IsFullyKnown() {
+			return
+		}
+		out, _, err := prg.Eval(map[string]any{"self": manifest})
+		if err != nil {
+			tflog.Debug(ctx, "Skipping validation rule that could not be evaluated", map[string]any{
+				"rule":  rule.Rule,
+				"error": err.Error(),
+			})
+			return
+		}
+		if out == types.False {
+			message := rule.Message
+			if message == "" {
+				message = fmt.Sprintf("failed rule: %s", rule.Rule)
+			}
+			if p.Equal(path.Empty()) {
+				diags.AddError("Validation rule failed", message)
+			} else {
+				diags.AddAttributeError(p, "Validation rule failed", message)
+			}
+		}
+		return
+	}
+
+	name, each := strings.CutSuffix(attributePath[0], "[*]")
+	field, _ := strings.CutSuffix(manifestPath[0], "[*]")
+	var attrs map[string]tftypes.Value
+	if err := config.As(&attrs); err != nil {
+		return
+	}
+	fields, ok := manifest.(map[string]any)
+	if !ok {
+		return
+	}
+	childConfig, childManifest, childPath := attrs[name], fields[field], p.AtName(name)
+
+	if !each {
+		evaluateValidationRule(ctx, c, rule, prg, childConfig, childManifest, attributePath[1:], manifestPath[1:], childPath, diags)
+		return
+	}
+	if !childConfig.IsKnown() || childConfig.IsNull() {
+		return
+	}
+	var items []tftypes.Value
+	if err := childConfig.As(&items); err != nil {
+		return
+	}
+	list, _ := childManifest.([]any)
+	for i := range items {
+		if i >= len(list) {
+			break
+		}
+		itemPath := childPath.AtListIndex(i)
+		if childConfig.Type().Is(tftypes.Set{}) {
+			itemPath = setElementPath(ctx, c, childPath, items[i])
+		}
+		evaluateValidationRule(ctx, c, rule, prg, items[i], list[i], attributePath[1:], manifestPath[1:], itemPath, diags)
+	}
+}
+
+// setElementPath returns the path of an element of the set at p, or p if
+// the element can't be converted to a framework value
+func setElementPath(ctx context.Context, c tfsdk.Config, p path.Path, element tftypes.Value) path.Path {
+	t, diags := c.Schema.TypeAtPath(ctx, p)
+	if diags.HasError() {
+		return p
+	}
+	setType, ok := t.(attr.TypeWithElementType)
+	if !ok {
+		return p
+	}
+	v, err := setType.ElementType().ValueFromTerraform(ctx, element)
+	if err != nil {
+		return p
+	}
+	return p.AtSetValue(v)
+}
+
+var (
+	validationRulesEnvOnce sync.Once
+	validationRulesEnv     *cel.Env
+	validationRulesEnvErr  error
+	validationRulePrograms sync.Map
+)
+
+// CompileValidationRule checks that the rule compiles in the environment
+// the rules are evaluated in, so the generator can leave out rules that
+// would never be evaluated
+func CompileValidationRule(rule string) error {
+	_, err := compileValidationRule(rule)
+	return err
+}
+
+// compileValidationRule compiles the rule, the programs are cached as the
+// same rules are evaluated for every plan
+func compileValidationRule(rule string) (cel.Program, error) {
+	if prg, ok := validationRulePrograms.Load(rule); ok {
+		return prg.(cel.Program), nil
+	}
+	validationRulesEnvOnce.Do(func() {
+		validationRulesEnv, validationRulesEnvErr = cel.NewEnv(
+			cel.Variable("self", cel.DynType),
+			ext.Strings(),
+			ext.Sets(),
+		)
+	})
+	if validationRulesEnvErr != nil {
+		return nil, validationRulesEnvErr
+	}
+	ast, iss := validationRulesEnv.Compile(rule)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	prg, err := validationRulesEnv.Program(ast, cel.CostLimit(validationRuleCostLimit))
+	if err != nil {
+		return nil, err
+	}
+	validationRulePrograms.Store(rule, prg)
+	return prg, nil
+}
+
+// manifestValue converts the configuration value to its manifest value,
+// using the manifest tags of the model type t for the field names. Null and
+// unknown values are left out, the rules that use unknown values are skipped
+// when walking the configuration.
+func manifestValue(t reflect.Type, v tftypes.Value) any {
+	if v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		if err := v.As(&s); err == nil {
+			return s
+		}
+	case v.Type().Is(tftypes.Bool):
+		var b bool
+		if err := v.As(&b); err == nil {
+			return b
+		}
+	case v.Type().Is(tftypes.Number):
+		var n big.Float
+		if err := v.As(&n); err != nil {
+			return nil
+		}
+		if n.IsInt() {
+			i, _ := n.Int64()
+			return i
+		}
+		f, _ := n.Float64()
+		return f
+	case v.Type().Is(tftypes.Object{}):
+		var attrs map[string]tftypes.Value
+		if err := v.As(&attrs); err != nil || t.Kind() != reflect.Struct {
+			return nil
+		}
+		m := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, field := f.Tag.Get("tfsdk"), f.Tag.Get("manifest")
+			if name == "" || field == "" {
+				continue
+			}
+			if attr, ok := attrs[name]; ok {
+				if e := manifestValue(f.Type, attr); e != nil {
+					m[field] = e
+				}
+			}
+		}
+		return m
+	case v.Type().Is(tftypes.List{}) || v.Type().Is(tftypes.Set{}):
+		var items []tftypes.Value
+		if err := v.As(&items); err != nil {
+			return nil
+		}
+		if t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		l := make([]any, len(items))
+		for i, item := range items {
+			l[i] = manifestValue(t, item)
+		}
+		return l
+	case v.Type().Is(tftypes.Map{}):
+		var elems map[string]tftypes.Value
+		if err := v.As(&elems); err != nil {
+			return nil
+		}
+		if t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		m := map[string]any{}
+		for k, e := range elems {
+			if e := manifestValue(t, e); e != nil {
+				m[k] = e
+			}
+		}
+		return m
+	}
+	return nil
+}
+
+// removeNulls removes the fields that are not set so they are absent from
+// the manifest as they would be on the API server
+func removeNulls(v any) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		m := map[string]any{}
+		for k, e := range vv {
+			if e = removeNulls(e); e != nil {
+				m[k] = e
+			}
+		}
+		return m
+	case []any:
+		l := make([]any, len(vv))
+		for i, e := range vv {
+			l[i] = removeNulls(e)
+		}
+		return l
+	}
+	return v
+}
+
+func splitPath(p string) []string {
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, ".")
+}
@@ -0,0 +1,192 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package autocrud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+type validationRulesTestModel struct {
+	Spec struct {
+		Replicas    types.Int64 `tfsdk:"replicas" manifest:"replicas"`
+		MinReplicas types.Int64 `tfsdk:"min_replicas" manifest:"minReplicas"`
+		Selector    struct {
+			App types.String `tfsdk:"app" manifest:"app"`
+		} `tfsdk:"selector" manifest:"selector"`
+		Ports []struct {
+			Port types.Int64 `tfsdk:"port" manifest:"port"`
+		} `tfsdk:"ports" manifest:"ports"`
+	} `tfsdk:"spec" manifest:"spec"`
+}
+
+var validationRulesTestSchema = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"spec": schema.SingleNestedAttribute{
+			Optional: true,
+			Attributes: map[string]schema.Attribute{
+				"replicas":     schema.Int64Attribute{Optional: true},
+				"min_replicas": schema.Int64Attribute{Optional: true},
+				"selector": schema.SingleNestedAttribute{
+					Optional: true,
+					Attributes: map[string]schema.Attribute{
+						"app": schema.StringAttribute{Optional: true},
+					},
+				},
+				"ports": schema.ListNestedAttribute{
+					Optional: true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"port": schema.Int64Attribute{Optional: true},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+// validationRulesTestConfig returns the configuration for the test schema,
+// selector is the app of the selector or nil or tftypes.UnknownValue
+func validationRulesTestConfig(replicas, minReplicas, selector any, ports ...any) tfsdk.Config {
+	ctx := context.Background()
+	specType := validationRulesTestSchema.Type().TerraformType(ctx).(tftypes.Object).AttributeTypes["spec"].(tftypes.Object)
+	portsType := specType.AttributeTypes["ports"].(tftypes.List)
+	selectorType := specType.AttributeTypes["selector"]
+	var selectorValue tftypes.Value
+	if app, ok := selector.(string); ok {
+		selectorValue = tftypes.NewValue(selectorType, map[string]tftypes.Value{
+			"app": tftypes.NewValue(tftypes.String, app),
+		})
+	} else {
+		selectorValue = tftypes.NewValue(selectorType, selector)
+	}
+	portValues := []tftypes.Value{}
+	for _, p := range ports {
+		portValues = append(portValues, tftypes.NewValue(portsType.ElementType, map[string]tftypes.Value{
+			"port": tftypes.NewValue(tftypes.Number, p),
+		}))
+	}
+	return tfsdk.Config{
+		Schema: validationRulesTestSchema,
+		Raw: tftypes.NewValue(validationRulesTestSchema.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"spec": tftypes.NewValue(specType, map[string]tftypes.Value{
+				"replicas":     tftypes.NewValue(tftypes.Number, replicas),
+				"min_replicas": tftypes.NewValue(tftypes.Number, minReplicas),
+				"selector":     selectorValue,
+				"ports":        tftypes.NewValue(portsType, portValues),
+			}),
+		}),
+	}
+}
+
+func TestValidationRulesValidator(t *testing.T) {
+	validator := ValidationRulesValidator[validationRulesTestModel]{
+		APIVersion: "example.com/v1",
+		Kind:       "Widget",
+		Rules: []ValidationRule{
+			{AttributePath: "spec", ManifestPath: "spec", Rule: "!has(self.minReplicas) || self.minReplicas <= self.replicas", Message: "minReplicas must not be more than replicas"},
+			{AttributePath: "spec.ports[*]", ManifestPath: "spec.ports[*]", Rule: "self.port > 1024"},
+			{AttributePath: "spec", ManifestPath: "spec", Rule: "self.replicas >= oldSelf.replicas"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		config   tfsdk.Config
+		expected map[string]string
+	}{
+		{"valid", validationRulesTestConfig(3, 1, "web", 8080), map[string]string{}},
+		{"not set", validationRulesTestConfig(3, nil, "web"), map[string]string{}},
+		{"unknown", validationRulesTestConfig(1, 2, "web", tftypes.UnknownValue), map[string]string{}},
+		{"invalid", validationRulesTestConfig(1, 2, "web", 8080, 80), map[string]string{
+			"spec":          "minReplicas must not be more than replicas",
+			"spec.ports[1]": "failed rule: self.port > 1024",
+		}},
+		{"null object", validationRulesTestConfig(1, 2, nil, 80), map[string]string{
+			"spec":          "minReplicas must not be more than replicas",
+			"spec.ports[0]": "failed rule: self.port > 1024",
+		}},
+		{"unknown object", validationRulesTestConfig(1, 2, tftypes.UnknownValue, 8080, 80), map[string]string{
+			"spec.ports[1]": "failed rule: self.port > 1024",
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &resource.ValidateConfigResponse{}
+			validator.ValidateResource(context.Background(), resource.ValidateConfigRequest{Config: tc.config}, resp)
+
+			errors := map[string]string{}
+			for _, d := range resp.Diagnostics.Errors() {
+				p := path.Empty()
+				if withPath, ok := d.(interface{ Path() path.Path }); ok {
+					p = withPath.Path()
+				}
+				errors[p.String()] = d.Detail()
+			}
+			assert.Equal(t, tc.expected, errors)
+		})
+	}
+}
+
+type validationRulesSetTestModel struct {
+	Hosts []struct {
+		Name types.String `tfsdk:"name" manifest:"name"`
+	} `tfsdk:"hosts" manifest:"hosts"`
+}
+
+func TestValidationRulesValidatorSet(t *testing.T) {
+	ctx := context.Background()
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"hosts": schema.SetNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{Optional: true},
+					},
+				},
+			},
+		},
+	}
+	hostsType := s.Type().TerraformType(ctx).(tftypes.Object).AttributeTypes["hosts"].(tftypes.Set)
+	hosts := []tftypes.Value{}
+	for _, name := range []string{"example.com", "localhost"} {
+		hosts = append(hosts, tftypes.NewValue(hostsType.ElementType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, name),
+		}))
+	}
+	config := tfsdk.Config{
+		Schema: s,
+		Raw: tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"hosts": tftypes.NewValue(hostsType, hosts),
+		}),
+	}
+	validator := ValidationRulesValidator[validationRulesSetTestModel]{
+		APIVersion: "example.com/v1",
+		Kind:       "Widget",
+		Rules: []ValidationRule{
+			{AttributePath: "hosts[*]", ManifestPath: "hosts[*]", Rule: "self.name != 'localhost'"},
+		},
+	}
+
+	resp := &resource.ValidateConfigResponse{}
+	validator.ValidateResource(ctx, resource.ValidateConfigRequest{Config: config}, resp)
+
+	errors := resp.Diagnostics.Errors()
+	if assert.Len(t, errors, 1) {
+		withPath, ok := errors[0].(interface{ Path() path.Path })
+		if assert.True(t, ok) {
+			assert.Equal(t, `hosts[Value({"name":"localhost"})]`, withPath.Path().String())
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+func runValidate(args []string) int {
+	flags, opts := newFlagSet("validate")
+	if err := parseFlags(flags, opts, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	generateFiles, err := opts.findConfigFiles()
+	if err != nil {
+		slog.Error("Error finding generator config files", "err", err)
+		return 1
+	}
+
+	invalid := 0
+	for _, f := range generateFiles {
+		config, err := opts.parseConfig(f)
+		if err != nil {
+			slog.Error("Invalid configuration", "filename", f, "err", err)
+			invalid++
+			continue
+		}
+		valid := true
+		for _, r := range config.Resources {
+			if r.Disabled {
+				continue
+			}
+			if _, err := os.Stat(r.OpenAPIConfig.Filename); err != nil {
+				slog.Error("OpenAPI specification not found", "filename", f, "resource", r.Name, "err", err)
+				valid = false
+			}
+		}
+		if !valid {
+			invalid++
+			continue
+		}
+		slog.Info("Configuration is valid", "filename", f, "resources", len(config.Resources))
+	}
+
+	if invalid > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runList(args []string) int {
+	flags, opts := newFlagSet("list")
+	if err := parseFlags(flags, opts, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	generateFiles, err := opts.findConfigFiles()
+	if err != nil {
+		slog.Error("Error finding generator config files", "err", err)
+		return 1
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONFIG\tRESOURCE\tPACKAGE\tAPI VERSION\tKIND\tDISABLED")
+	failed := false
+	for _, f := range generateFiles {
+		config, err := opts.parseConfig(f)
+		if err != nil {
+			slog.Error("Error parsing configuration", "filename", f, "err", err)
+			failed = true
+			continue
+		}
+		for _, r := range config.Resources {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\n", f, r.Name, r.Package, r.APIVersion, r.Kind, r.Disabled)
+		}
+	}
+	tw.Flush()
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+func runDiff(args []string) int {
+	flags, opts := newFlagSet("diff")
+	if err := parseFlags(flags, opts, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	generateFiles, err := opts.findConfigFiles()
+	if err != nil {
+		slog.Error("Error finding generator config files", "err", err)
+		return 1
+	}
+
+	unacknowledged := 0
+	for _, f := range generateFiles {
+		config, err := opts.parseConfig(f)
+		if err != nil {
+			slog.Error("Error parsing configuration", "filename", f, "err", err)
+			return 1
+		}
+		n, err := diffSchemas(f, config)
+		if err != nil {
+			slog.Error("Error comparing schemas", "filename", f, "err", err)
+			return 1
+		}
+		unacknowledged += n
+	}
+	if unacknowledged > 0 {
+		slog.Error("Found breaking schema changes, add their paths to acknowledged_breaking_changes if they are intended", "count", unacknowledged)
+		return 1
+	}
+	return 0
+}
+
+func runClean(args []string) int {
+	flags, opts := newFlagSet("clean")
+	if err := parseFlags(flags, opts, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	generateFiles, err := opts.findConfigFiles()
+	if err != nil {
+		slog.Error("Error finding generator config files", "err", err)
+		return 1
+	}
+
+	configFiles := []string{}
+	configs := []generator.GeneratorConfig{}
+	failedConfigFiles := []string{}
+	for _, f := range generateFiles {
+		config, err := opts.parseConfig(f)
+		if err != nil {
+			slog.Error("Error parsing configuration", "filename", f, "err", err)
+			failedConfigFiles = append(failedConfigFiles, f)
+			continue
+		}
+		configFiles = append(configFiles, f)
+		configs = append(configs, config)
+	}
+	unselectedFiles, err := opts.unselectedConfigFiles(generateFiles)
+	if err != nil {
+		slog.Error("Error finding generator config files", "err", err)
+		return 1
+	}
+	if err := syncManifest(&fileWriter{}, opts.root, configFiles, configs, append(failedConfigFiles, unselectedFiles...), true); err != nil {
+		slog.Error("Error removing orphaned generated files", "err", err)
+		return 1
+	}
+
+	tempDir := opts.codegenTempDir()
+	if err := os.RemoveAll(tempDir); err != nil {
+		slog.Error("Error removing temporary files", "dir", tempDir, "err", err)
+		return 1
+	}
+	slog.Info("Removed temporary files", "dir", tempDir)
+	return 0
+}
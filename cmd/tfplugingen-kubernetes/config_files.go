@@ -0,0 +1,135 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+var configFilePattern = regexp.MustCompile(`generate(_.+)?\.hcl`)
+
+// defaultExcludedDirs are never searched for config files
+var defaultExcludedDirs = []string{".git", "vendor", "node_modules", ".codegen-tmp"}
+
+// findConfigFiles returns the config files passed with -config, or
+// searches the root directory for files matching configFilePattern
+func (o *options) findConfigFiles() ([]string, error) {
+	if len(o.configFiles) > 0 {
+		return o.configFiles, nil
+	}
+	generateFiles, err := o.searchConfigFiles()
+	for _, f := range generateFiles {
+		slog.Info("Found generator config file", "filename", f)
+	}
+	return generateFiles, err
+}
+
+// searchConfigFiles searches the root directory for config files that are
+// not excluded
+func (o *options) searchConfigFiles() ([]string, error) {
+	generateFiles := []string{}
+	err := filepath.WalkDir(o.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != o.root && o.isExcluded(path, d) {
+			slog.Debug("Skipping excluded path", "path", path)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && configFilePattern.MatchString(d.Name()) {
+			generateFiles = append(generateFiles, path)
+		}
+		return nil
+	})
+	return generateFiles, err
+}
+
+// unselectedConfigFiles returns the config files under the root directory
+// that are left out by -config or -exclude
+func (o *options) unselectedConfigFiles(selected []string) ([]string, error) {
+	if len(o.configFiles) == 0 && len(o.excludes) == 0 {
+		return nil, nil
+	}
+	all, err := (&options{root: o.root}).searchConfigFiles()
+	if err != nil {
+		return nil, err
+	}
+	unselected := []string{}
+	for _, f := range all {
+		if !slices.ContainsFunc(selected, func(s string) bool { return sameFile(s, f) }) {
+			unselected = append(unselected, f)
+		}
+	}
+	return unselected, nil
+}
+
+// sameFile reports whether two paths name the same file, as -config paths
+// are relative to the working directory and can be written differently
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// parseConfig parses a config file, the OpenAPI specifications and
+// snapshots it refers to are relative to the root directory
+func (o *options) parseConfig(filename string) (generator.GeneratorConfig, error) {
+	config, err := generator.ParseHCLConfig(filename)
+	if err != nil {
+		return config, err
+	}
+	for i := range config.Resources {
+		r := &config.Resources[i]
+		r.OpenAPIConfig.Filename = o.rootPath(r.OpenAPIConfig.Filename)
+		for j := range r.StateUpgrades {
+			u := &r.StateUpgrades[j]
+			if u.OpenAPIConfig != nil {
+				u.OpenAPIConfig.Filename = o.rootPath(u.OpenAPIConfig.Filename)
+			}
+			if u.Snapshot != "" {
+				u.Snapshot = o.rootPath(u.Snapshot)
+			}
+		}
+	}
+	return config, nil
+}
+
+// rootPath resolves a path from a config file against the root directory
+func (o *options) rootPath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(o.root, path)
+}
+
+func (o *options) isExcluded(path string, d fs.DirEntry) bool {
+	if d.IsDir() && slices.Contains(defaultExcludedDirs, d.Name()) {
+		return true
+	}
+	rel, err := filepath.Rel(o.root, path)
+	if err != nil {
+		rel = path
+	}
+	for _, pattern := range o.excludes {
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, d.Name()); ok {
+			return true
+		}
+	}
+	return false
+}
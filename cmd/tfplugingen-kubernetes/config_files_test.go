@@ -0,0 +1,97 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeTestConfig writes a config file for a single resource to dir
+func writeTestConfig(t *testing.T, dir, name, prefix string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "generate.hcl")
+	config := fmt.Sprintf(`resource %q {
+  package                = %q
+  api_version            = "v1"
+  kind                   = "Widget"
+  description            = "Widgets"
+  output_filename_prefix = %q
+
+  openapi {
+    filename    = "openapi/api.json"
+    create_path = "/api/v1/widgets"
+    read_path   = "/api/v1/widgets/{name}"
+  }
+
+  generate {
+    schema = true
+  }
+}
+`, name, filepath.Base(dir), prefix)
+	if err := os.WriteFile(filename, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestUnselectedConfigFiles(t *testing.T) {
+	root := t.TempDir()
+	a := writeTestConfig(t, filepath.Join(root, "a"), "kubernetes_a", "a")
+	b := writeTestConfig(t, filepath.Join(root, "b"), "kubernetes_b", "b")
+
+	for _, tc := range []struct {
+		name     string
+		opts     options
+		expected []string
+	}{
+		{"all", options{root: root}, nil},
+		{"config", options{root: root, configFiles: stringSliceFlag{a}}, []string{b}},
+		{"exclude", options{root: root, excludes: stringSliceFlag{"a"}}, []string{a}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			selected, err := tc.opts.findConfigFiles()
+			if err != nil {
+				t.Fatal(err)
+			}
+			unselected, err := tc.opts.unselectedConfigFiles(selected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(unselected, tc.expected) {
+				t.Errorf("expected %v got %v", tc.expected, unselected)
+			}
+		})
+	}
+}
+
+func TestParseConfigRootPaths(t *testing.T) {
+	root := t.TempDir()
+	filename := writeTestConfig(t, filepath.Join(root, "widget"), "kubernetes_widget", "widget")
+
+	opts := &options{root: root}
+	config, err := opts.parseConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := filepath.Join(root, "openapi/api.json")
+	if config.Resources[0].OpenAPIConfig.Filename != expected {
+		t.Errorf("expected the OpenAPI path to be relative to the root %q got %q", expected, config.Resources[0].OpenAPIConfig.Filename)
+	}
+}
+
+func TestProviderFlagsOnlyForGenerate(t *testing.T) {
+	for _, c := range []string{"validate", "list", "diff", "clean"} {
+		flags, _ := newFlagSet(c)
+		if flags.Lookup("provider-dir") != nil || flags.Lookup("provider-package") != nil {
+			t.Errorf("expected %s not to have the provider flags", c)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+func schemaSnapshotFilename(r generator.ResourceConfig) string {
+	return fmt.Sprintf("%s_schema_snapshot.json", r.OutputFilenamePrefix)
+}
+
+// diffSchemas compares the schema generated for each resource against the
+// snapshot stored next to the generated code and returns the number of
+// breaking changes that have not been acknowledged in the configuration
+func diffSchemas(path string, config generator.GeneratorConfig) (int, error) {
+	wd := filepath.Dir(path)
+
+	unacknowledged := 0
+	for _, r := range config.Resources {
+		if r.Disabled || !r.Generate.Schema {
+			continue
+		}
+
+		snapshotFilename := filepath.Join(wd, schemaSnapshotFilename(r))
+		previous, err := generator.ReadSchemaSnapshot(snapshotFilename)
+		if errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("No schema snapshot found, skipping", "resource", r.Name, "filename", snapshotFilename)
+			continue
+		} else if err != nil {
+			return unacknowledged, err
+		}
+
+		spec, err := generator.GenerateResourceSpec(r)
+		if err != nil {
+			return unacknowledged, fmt.Errorf("error generating provider spec: %v", err)
+		}
+		gen := generator.NewResourceGenerator(generator.DefaultProviderConfig(), r, spec)
+		current := generator.NewSchemaSnapshot(gen.Schema)
+
+		for _, c := range generator.DiffSchemaSnapshots(previous, current) {
+			switch {
+			case !c.Breaking:
+				slog.Info("Schema change", "resource", r.Name, "path", c.Path, "change", c.Description)
+			case slices.Contains(r.AcknowledgedBreakingChanges, c.Path):
+				slog.Warn("Acknowledged breaking schema change", "resource", r.Name, "path", c.Path, "change", c.Description)
+			default:
+				slog.Error("Breaking schema change", "resource", r.Name, "path", c.Path, "change", c.Description)
+				unacknowledged++
+			}
+		}
+	}
+	return unacknowledged, nil
+}
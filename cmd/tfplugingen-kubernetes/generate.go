@@ -0,0 +1,486 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+func runGenerate(args []string) int {
+	flags, opts := newFlagSet("generate")
+	opts.addProviderFlags(flags)
+	check := flags.Bool("check", false, "check that the generated code is up to date without writing any files")
+	parallel := flags.Int("parallel", 1, "number of resources to generate concurrently")
+	force := flags.Bool("force", false, "generate every resource even if its inputs have not changed since the last run")
+	prune := flags.Bool("prune", false, "remove generated files that are no longer produced by the configuration")
+	genAIBaseURL := flags.String("genai-base-url", "", "base URL of the OpenAI compatible API used to generate validators when gen_ai_validation is set, defaults to the OpenAI API")
+	genAIModel := flags.String("genai-model", generator.DefaultValidatorModel, "model used to generate validators when gen_ai_validation is set")
+	refreshValidators := flags.Bool("refresh-validators", false, "generate validators again instead of using the validators cache when gen_ai_validation is set")
+	reviewValidators := flags.Bool("review-validators", false, "write new validators to a review file instead of the generated code when gen_ai_validation is set")
+	if err := parseFlags(flags, opts, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *parallel < 1 {
+		fmt.Fprintln(os.Stderr, "-parallel must be at least 1")
+		return 2
+	}
+	generateFiles, err := opts.findConfigFiles()
+	if err != nil {
+		slog.Error("Error finding generator config files", "err", err)
+		return 1
+	}
+
+	generator.CodegenTempDir = opts.codegenTempDir()
+
+	w := &fileWriter{check: *check}
+	results := []result{}
+
+	// all of the configuration is parsed up front as the provider block
+	// can be set in any of the files
+	configFiles := []string{}
+	configs := []generator.GeneratorConfig{}
+	failedConfigFiles := []string{}
+	for _, f := range generateFiles {
+		config, err := opts.parseConfig(f)
+		if err != nil {
+			slog.Error("Error parsing configuration", "filename", f, "err", err)
+			results = append(results, result{ConfigFile: f, Status: statusFailed, Err: err})
+			failedConfigFiles = append(failedConfigFiles, f)
+			continue
+		}
+		configFiles = append(configFiles, f)
+		configs = append(configs, config)
+	}
+
+	// the config files left out by -config or -exclude still provide the
+	// provider block and their resources stay in the resources list
+	unselectedFiles, err := opts.unselectedConfigFiles(generateFiles)
+	if err != nil {
+		slog.Error("Error finding generator config files", "err", err)
+		return 1
+	}
+	unselectedConfigs := []generator.GeneratorConfig{}
+	writeResourcesList := true
+	for _, f := range unselectedFiles {
+		config, err := opts.parseConfig(f)
+		if err != nil {
+			slog.Warn("Error parsing configuration that is not selected, the resources list is not updated", "filename", f, "err", err)
+			writeResourcesList = false
+			continue
+		}
+		unselectedConfigs = append(unselectedConfigs, config)
+	}
+
+	provider, err := opts.providerConfig(append(slices.Clone(configs), unselectedConfigs...))
+	if err != nil {
+		slog.Error("Error resolving provider configuration", "err", err)
+		return 1
+	}
+
+	// resources whose inputs are unchanged since the last run are skipped,
+	// everything is generated in check mode so that hand edits are found
+	lockFilePath := filepath.Join(opts.root, lockFilename)
+	lock, err := readLockFile(lockFilePath)
+	if err != nil {
+		slog.Warn("Error reading lock file, generating all resources", "filename", lockFilePath, "err", err)
+	}
+	hasher := generator.NewInputsHasher()
+
+	jobs := []generateJob{}
+	generatedResources := []generator.ResourceConfig{}
+	changedResources := []generator.ResourceConfig{}
+	for i, config := range configs {
+		for _, r := range config.Resources {
+			job := generateJob{configFile: configFiles[i], resource: r}
+			if !r.Disabled {
+				// the resource is added to the resources list even if it fails so
+				// that the list always reflects the configuration
+				generatedResources = append(generatedResources, r)
+
+				job.hash, err = hasher.ResourceHash(provider, r)
+				if err != nil {
+					slog.Warn("Error hashing resource inputs", "resource", r.Name, "err", err)
+				}
+				// resources with genAI validators are always generated as
+				// the validators cache is not part of the inputs hash
+				job.unchanged = !*check && !*force && job.hash != "" && !r.Generate.GenAIValidation &&
+					lock.Resources[r.Name].Hash == job.hash && generatedFilesExist(job)
+				if !job.unchanged {
+					changedResources = append(changedResources, r)
+				}
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	for _, config := range unselectedConfigs {
+		for _, r := range config.Resources {
+			if !r.Disabled {
+				generatedResources = append(generatedResources, r)
+			}
+		}
+	}
+	g := &resourcesGenerator{
+		writer:   w,
+		provider: provider,
+		specs:    generator.NewResourceSpecCache(changedResources),
+		opts:     opts,
+		validators: &generator.CheckedValidatorGenerator{
+			Generator: generator.NewOpenAIValidatorGenerator(*genAIBaseURL, os.Getenv("OPENAI_API_KEY"), *genAIModel),
+			MaxFixes:  generator.DefaultMaxValidatorFixes,
+		},
+		validatorOptions: validatorOptions{
+			model:   *genAIModel,
+			refresh: *refreshValidators,
+			review:  *reviewValidators,
+		},
+	}
+	jobResults := g.generate(jobs, *parallel)
+	results = append(results, jobResults...)
+
+	if !*check {
+		newLock := lock.unselected(unselectedFiles)
+		for i, job := range jobs {
+			switch jobResults[i].Status {
+			case statusOK, statusUnchanged:
+				newLock.Resources[job.resource.Name] = lockedResource{ConfigFile: job.configFile, Hash: job.hash}
+			}
+		}
+		if err := newLock.write(lockFilePath); err != nil {
+			slog.Error("Error writing lock file", "filename", lockFilePath, "err", err)
+		}
+	}
+
+	if err := syncManifest(w, opts.root, configFiles, configs, append(failedConfigFiles, unselectedFiles...), *prune); err != nil {
+		slog.Error("Error updating manifest", "err", err)
+		results = append(results, result{ConfigFile: "-", Resource: "manifest", Status: statusFailed, Err: err})
+	}
+
+	// generate resources list file
+	outputFilename := "resources_list_gen.go"
+	resourcesList := generator.NewResourcesListGenerator(generatedResources, provider)
+	if !writeResourcesList {
+		slog.Warn("Skipping resources list as not every config file could be parsed", "filename", outputFilename)
+	} else if err := writeSourceFile(w, provider.OutputDir, outputFilename, resourcesList.Render); err != nil {
+		slog.Error("Error generating resources list", "err", err)
+		results = append(results, result{ConfigFile: "-", Resource: "resources list", Status: statusFailed, Err: err})
+	} else {
+		slog.Info("Generated resources list source file", "filename", outputFilename)
+	}
+
+	printSummary(os.Stdout, results)
+
+	if len(w.stale) > 0 {
+		sort.Strings(w.stale)
+		for _, f := range w.stale {
+			slog.Error("Generated file is stale", "filename", f)
+		}
+		slog.Error("Generated code is not up to date, run the generator to update it", "count", len(w.stale))
+		return 1
+	}
+
+	if failed := countFailed(results); failed > 0 {
+		slog.Error("Code generation failed", "failed", failed)
+		return 1
+	}
+	return 0
+}
+
+// generatedFilesExist checks the files for a resource exist, so that
+// deleting a generated or scaffolded file causes it to be written again
+func generatedFilesExist(job generateJob) bool {
+	r := job.resource
+	files := generatedFilenames(r)
+	if r.Generate.CRUDStubs {
+		files = append(files, fmt.Sprintf("%s_crud.go", r.OutputFilenamePrefix))
+	}
+	if r.Generate.CRUDAutoOptions != nil && !r.Generate.CRUDAutoOptions.Hooks.IsEmpty() {
+		files = append(files, fmt.Sprintf("%s_hooks.go", r.OutputFilenamePrefix))
+	}
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(job.configFile), f)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// writeSourceFile renders the code for a generated file and overwrites it
+func writeSourceFile(w *fileWriter, wd, filename string, render func() (string, error)) error {
+	_, err := writeSourceFileWithPolicy(w, wd, filename, render, generator.Overwrite)
+	return err
+}
+
+// writeSourceFileWithPolicy renders the code for a generated file and writes
+// it using the policy. It returns false if the existing file was left unchanged.
+func writeSourceFileWithPolicy(w *fileWriter, wd, filename string, render func() (string, error), policy generator.WritePolicy) (bool, error) {
+	code, err := render()
+	if err != nil {
+		return false, fmt.Errorf("error rendering %s: %v", filename, err)
+	}
+	written, err := w.WriteSourceFile(wd, filename, code, policy)
+	if err != nil {
+		return false, fmt.Errorf("error writing %s: %v", filename, err)
+	}
+	return written, nil
+}
+
+func (g *resourcesGenerator) generateResource(log *slog.Logger, wd string, r generator.ResourceConfig) error {
+	w := g.writer
+	spec, err := g.specs.ResourceSpec(r)
+	if err != nil {
+		return fmt.Errorf("error generating provider spec: %v", err)
+	}
+	if err := generator.ValidateAttributes(r, spec); err != nil {
+		return fmt.Errorf("invalid attribute configuration: %v", err)
+	}
+
+	gen := generator.NewResourceGenerator(g.provider, r, spec)
+
+	// genAI validators are generated before the schema is written as
+	// attributes without a validator are left out of it, in check mode only
+	// the cached validators are used so the schema matches the last run
+	if r.Generate.GenAIValidation {
+		if err := g.generateGenAIValidators(log, wd, r, &gen); err != nil {
+			return err
+		}
+	}
+
+	// generate resource
+	outputFilename := fmt.Sprintf("%s_gen.go", r.OutputFilenamePrefix)
+	if err := writeSourceFile(w, wd, outputFilename, gen.GenerateResourceCode); err != nil {
+		return err
+	}
+	log.Info("Generated resource source file", "filename", outputFilename)
+
+	// generate schema
+	if r.Generate.Schema {
+		outputFilename = fmt.Sprintf("%s_schema_gen.go", r.OutputFilenamePrefix)
+		if err := writeSourceFile(w, wd, outputFilename, gen.GenerateSchemaFunctionCode); err != nil {
+			return err
+		}
+		log.Info("Generated schema source file", "filename", outputFilename)
+
+		outputFilename = schemaSnapshotFilename(r)
+		snapshot, err := generator.MarshalSchemaSnapshot(generator.NewSchemaSnapshot(gen.Schema))
+		if err != nil {
+			return fmt.Errorf("error generating schema snapshot: %v", err)
+		}
+		if err := w.WriteFile(wd, outputFilename, snapshot); err != nil {
+			return fmt.Errorf("error writing %s: %v", outputFilename, err)
+		}
+		log.Info("Generated schema snapshot file", "filename", outputFilename)
+	}
+
+	// generate state upgraders
+	if len(r.StateUpgrades) > 0 {
+		for _, u := range r.StateUpgrades {
+			priorSpec, err := generator.GeneratePriorResourceSpec(r, u)
+			if err != nil {
+				return fmt.Errorf("error generating provider spec for schema version %d: %v", u.Version, err)
+			}
+			gen.StateUpgrades = append(gen.StateUpgrades, generator.NewStateUpgradeGenerator(r, u, priorSpec))
+		}
+		outputFilename = fmt.Sprintf("%s_state_upgrade_gen.go", r.OutputFilenamePrefix)
+		if err := writeSourceFile(w, wd, outputFilename, gen.GenerateStateUpgradeCode); err != nil {
+			return err
+		}
+		log.Info("Generated state upgrade source file", "filename", outputFilename)
+	}
+
+	// generate state movers
+	if len(r.MoveFrom) > 0 {
+		outputFilename = fmt.Sprintf("%s_move_state_gen.go", r.OutputFilenamePrefix)
+		if err := writeSourceFile(w, wd, outputFilename, gen.GenerateMoveStateCode); err != nil {
+			return err
+		}
+		log.Info("Generated move state source file", "filename", outputFilename)
+	}
+
+	// generate CRUD stubs, these belong to the user once they have been written
+	if r.Generate.CRUDStubs {
+		outputFilename = fmt.Sprintf("%s_crud.go", r.OutputFilenamePrefix)
+		written, err := writeSourceFileWithPolicy(w, wd, outputFilename, gen.GenerateCRUDStubCode, generator.CreateOnly)
+		if err != nil {
+			return err
+		}
+		if written {
+			log.Info("Generated CRUD stub source file", "filename", outputFilename)
+		} else if !w.check {
+			log.Debug("File already scaffolded. Delete to regenerate", "filename", outputFilename)
+		}
+	}
+
+	// generate auto CRUD functions
+	if r.Generate.CRUDAuto {
+		outputFilename = fmt.Sprintf("%s_crud_gen.go", r.OutputFilenamePrefix)
+		if err := writeSourceFile(w, wd, outputFilename, gen.GenerateAutoCRUDCode); err != nil {
+			return err
+		}
+		log.Info("Generated autocrud source file", "filename", outputFilename)
+	}
+
+	// generate hooks, hook methods that have been enabled since the file
+	// was written are added to it
+	if err := generateHooks(log, w, wd, r, gen); err != nil {
+		return err
+	}
+
+	// generate model
+	if r.Generate.Model {
+		outputFilename = fmt.Sprintf("%s_model_gen.go", r.OutputFilenamePrefix)
+		if err := writeSourceFile(w, wd, outputFilename, gen.GenerateModelCode); err != nil {
+			return err
+		}
+		log.Info("Generated model source file", "filename", outputFilename)
+	}
+
+	// generate validators for the CEL rules in the OpenAPI spec
+	if r.Generate.CELValidation {
+		rules := generator.GenerateValidationRules(g.specs.OpenAPIDocument(r), r, spec)
+		gen.ValidationRules = rules
+		outputFilename = fmt.Sprintf("%s_cel_validation_gen.go", r.OutputFilenamePrefix)
+		if err := writeSourceFile(w, wd, outputFilename, gen.GenerateCELValidationCode); err != nil {
+			return err
+		}
+		log.Info("Generated CEL validation source file", "filename", outputFilename, "rules", len(rules))
+	}
+
+	// generate genAI Validators
+	if r.Generate.GenAIValidation {
+		outputFilename = fmt.Sprintf("%s_validators_gen.go", r.OutputFilenamePrefix)
+		if err := writeSourceFile(w, wd, outputFilename, gen.GenerateGenAIValidatorsCode); err != nil {
+			return err
+		}
+		log.Info("Generated genAI based validators file", "filename", outputFilename)
+	}
+
+	// generate tests for the validators
+	if r.Generate.ValidatorTests {
+		gen.GenerateValidatorTests(g.specs.OpenAPIDocument(r), spec)
+		outputFilename = fmt.Sprintf("%s_validators_gen_test.go", r.OutputFilenamePrefix)
+		if err := writeSourceFile(w, wd, outputFilename, gen.GenerateValidatorTestsCode); err != nil {
+			return err
+		}
+		log.Info("Generated validator tests file", "filename", outputFilename)
+	}
+
+	return nil
+}
+
+// generateGenAIValidators generates the validators for the resource using
+// the validators cache next to the configuration file, writing the updated
+// cache and, in review mode, the new validators to a review file. In check
+// mode the model is not used, validators that are not cached are left out.
+func (g *resourcesGenerator) generateGenAIValidators(log *slog.Logger, wd string, r generator.ResourceConfig, gen *generator.ResourceGenerator) error {
+	cacheFilename := fmt.Sprintf("%s_validators_cache.json", r.OutputFilenamePrefix)
+	cache, err := generator.ReadValidatorCache(filepath.Join(wd, cacheFilename))
+	if err != nil {
+		return fmt.Errorf("error reading validators cache: %v", err)
+	}
+	cached := &generator.CachedValidatorGenerator{
+		Generator: g.validators,
+		Model:     g.validatorOptions.model,
+		Cache:     cache,
+		Refresh:   g.validatorOptions.refresh,
+		Review:    g.validatorOptions.review,
+		Check:     true,
+	}
+	if g.writer.check {
+		cached.Generator = cachedValidatorsOnly{}
+		cached.Refresh, cached.Review = false, false
+	}
+	gen.ValidatorGenerator = cached
+	if err := gen.GenerateGenAIValidators(context.Background()); err != nil {
+		return err
+	}
+	if err := gen.CheckGenAIValidators(); err != nil {
+		return err
+	}
+
+	contents, err := cached.UpdatedCache().Marshal()
+	if err != nil {
+		return fmt.Errorf("error marshalling validators cache: %v", err)
+	}
+	if err := g.writer.WriteFile(wd, cacheFilename, contents); err != nil {
+		return fmt.Errorf("error writing %s: %v", cacheFilename, err)
+	}
+	if !g.writer.check {
+		log.Info("Updated validators cache", "filename", cacheFilename, "validators", len(cached.UpdatedCache().Validators))
+	}
+
+	if len(cached.Suggestions.Validators) > 0 {
+		reviewFilename := fmt.Sprintf("%s_validators_review.json", r.OutputFilenamePrefix)
+		contents, err := cached.Suggestions.Marshal()
+		if err != nil {
+			return fmt.Errorf("error marshalling validators for review: %v", err)
+		}
+		if err := g.writer.WriteFile(wd, reviewFilename, contents); err != nil {
+			return fmt.Errorf("error writing %s: %v", reviewFilename, err)
+		}
+		log.Warn("New validators need to be reviewed, move them to the validators cache to use them", "filename", reviewFilename, "validators", len(cached.Suggestions.Validators))
+	}
+	return nil
+}
+
+// cachedValidatorsOnly is used in check mode so that only the validators in
+// the cache are used, attributes without one are generated without a validator
+type cachedValidatorsOnly struct{}
+
+func (cachedValidatorsOnly) GenerateValidator(ctx context.Context, req generator.ValidatorRequest) (generator.GeneratedValidator, error) {
+	return generator.GeneratedValidator{}, nil
+}
+
+// generateHooks writes the hooks file, adding the hook methods that are
+// missing from an existing file and reporting the ones that are no longer called
+func generateHooks(log *slog.Logger, w *fileWriter, wd string, r generator.ResourceConfig, gen generator.ResourceGenerator) error {
+	outputFilename := fmt.Sprintf("%s_hooks.go", r.OutputFilenamePrefix)
+	existing, err := os.ReadFile(filepath.Join(wd, outputFilename))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if existing != nil {
+		unused, err := gen.UnusedHookMethods(existing)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %v", outputFilename, err)
+		}
+		for _, m := range unused {
+			log.Warn("Hook method does not implement an autocrud hook interface so is never called, update its signature or remove it", "filename", outputFilename, "method", m)
+		}
+	}
+
+	if r.Generate.CRUDAutoOptions == nil || r.Generate.CRUDAutoOptions.Hooks.IsEmpty() {
+		return nil
+	}
+
+	if existing != nil {
+		missing, err := gen.MissingHookMethods(existing)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %v", outputFilename, err)
+		}
+		for _, m := range missing {
+			log.Info("Adding hook method", "filename", outputFilename, "method", m)
+		}
+	}
+
+	written, err := writeSourceFileWithPolicy(w, wd, outputFilename, gen.GenerateAutoCRUDHooksCode, generator.MergeNewMethods)
+	if err != nil {
+		return err
+	}
+	if written {
+		log.Info("Generated autocrud hooks file", "filename", outputFilename)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+// fakeValidatorGenerator only returns code for the validator types it has
+type fakeValidatorGenerator struct {
+	codes map[string]string
+	calls int
+}
+
+func (g *fakeValidatorGenerator) GenerateValidator(ctx context.Context, req generator.ValidatorRequest) (generator.GeneratedValidator, error) {
+	g.calls++
+	return generator.GeneratedValidator{Code: g.codes[req.Type]}, nil
+}
+
+const nameValidator = `type kubernetes_widget_v1_name_validator struct{}
+
+func (v kubernetes_widget_v1_name_validator) Description(ctx context.Context) string {
+	return "name must not be empty"
+}
+
+func (v kubernetes_widget_v1_name_validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v kubernetes_widget_v1_name_validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid name", "name must not be empty")
+	}
+}`
+
+func TestCheckGenAIValidators(t *testing.T) {
+	wd := t.TempDir()
+	description := "The name."
+	spec := specresource.Resource{Name: "kubernetes_widget_v1", Schema: &specresource.Schema{
+		Attributes: specresource.Attributes{
+			{Name: "name", String: &specresource.StringAttribute{Description: &description}},
+			{Name: "replicas", Int64: &specresource.Int64Attribute{Description: &description}},
+		},
+	}}
+	r := generator.ResourceConfig{
+		Name:                 "kubernetes_widget_v1",
+		Package:              "widget",
+		OutputFilenamePrefix: "widget",
+		APIVersion:           "example.com/v1",
+		Kind:                 "Widget",
+		Generate:             generator.GenerateConfig{Schema: true, GenAIValidation: true},
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// the model only writes a validator for the name, so the replicas
+	// attribute is generated without one
+	backend := &fakeValidatorGenerator{codes: map[string]string{
+		"kubernetes_widget_v1_name_validator": nameValidator,
+	}}
+	generate := func(w *fileWriter) {
+		t.Helper()
+		g := &resourcesGenerator{writer: w, validators: backend, validatorOptions: validatorOptions{model: "test"}}
+		gen := generator.NewResourceGenerator(generator.DefaultProviderConfig(), r, spec)
+		if err := g.generateGenAIValidators(log, wd, r, &gen); err != nil {
+			t.Fatal(err)
+		}
+		for filename, render := range map[string]func() (string, error){
+			"widget_schema_gen.go":     gen.GenerateSchemaFunctionCode,
+			"widget_validators_gen.go": gen.GenerateGenAIValidatorsCode,
+		} {
+			if err := writeSourceFile(w, wd, filename, render); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	generate(&fileWriter{})
+	if backend.calls != 2 {
+		t.Fatalf("expected 2 validators to be generated got %d", backend.calls)
+	}
+
+	w := &fileWriter{check: true}
+	generate(w)
+	if backend.calls != 2 {
+		t.Errorf("expected the model not to be used in check mode")
+	}
+	if len(w.stale) > 0 {
+		t.Errorf("expected no stale files got %v", w.stale)
+	}
+}
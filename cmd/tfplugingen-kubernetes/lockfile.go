@@ -0,0 +1,69 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+// lockFilename is the name of the lock file written to the root directory
+const lockFilename = ".tfplugingen-kubernetes.lock.json"
+
+// lockFile records a hash of the inputs used to generate each resource so
+// that resources whose inputs have not changed can be skipped
+type lockFile struct {
+	Resources map[string]lockedResource `json:"resources"`
+}
+
+type lockedResource struct {
+	ConfigFile string `json:"config_file"`
+	Hash       string `json:"hash"`
+}
+
+func newLockFile() lockFile {
+	return lockFile{Resources: map[string]lockedResource{}}
+}
+
+// readLockFile reads the lock file, returning an empty lock file if it does not exist
+func readLockFile(filename string) (lockFile, error) {
+	l := newLockFile()
+	contents, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	} else if err != nil {
+		return l, err
+	}
+	if err := json.Unmarshal(contents, &l); err != nil {
+		return l, fmt.Errorf("error parsing lock file %s: %v", filename, err)
+	}
+	if l.Resources == nil {
+		l.Resources = map[string]lockedResource{}
+	}
+	return l, nil
+}
+
+func (l lockFile) write(filename string) error {
+	contents, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(contents, '\n'), 0o644)
+}
+
+// unselected returns a lock file with the resources from the config files
+// that are not selected in this run, so they are still skipped next time
+func (l lockFile) unselected(configFiles []string) lockFile {
+	kept := newLockFile()
+	for name, r := range l.Resources {
+		if slices.ContainsFunc(configFiles, func(f string) bool { return sameFile(f, r.ConfigFile) }) {
+			kept.Resources[name] = r
+		}
+	}
+	return kept
+}
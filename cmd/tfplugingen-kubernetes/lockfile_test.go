@@ -0,0 +1,22 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import "testing"
+
+func TestLockFileUnselected(t *testing.T) {
+	lock := lockFile{Resources: map[string]lockedResource{
+		"kubernetes_a": {ConfigFile: "pkg/a/generate.hcl", Hash: "a"},
+		"kubernetes_b": {ConfigFile: "pkg/b/generate.hcl", Hash: "b"},
+	}}
+
+	// pkg/a was selected with -config so only pkg/b is carried over
+	kept := lock.unselected([]string{"./pkg/b/generate.hcl"})
+	if len(kept.Resources) != 1 || kept.Resources["kubernetes_b"].Hash != "b" {
+		t.Errorf("expected only the unselected resource to be kept got %v", kept.Resources)
+	}
+	if len(lock.unselected(nil).Resources) != 0 {
+		t.Errorf("expected no resources to be kept when every config file is selected")
+	}
+}
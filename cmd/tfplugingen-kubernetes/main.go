@@ -4,12 +4,13 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"io/fs"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
-	"regexp"
+	"strings"
 	"time"
 
 	"github.com/lmittmann/tint"
@@ -17,142 +18,147 @@ import (
 	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
 )
 
-var configFilePattern = regexp.MustCompile(`generate(_.+)?\.hcl`)
+// command is a subcommand of the generator CLI
+type command struct {
+	name        string
+	description string
+	run         func(args []string) int
+}
+
+var commands = []command{
+	{"generate", "Generate code for the resources in the generator config files (default)", runGenerate},
+	{"validate", "Validate the generator config files", runValidate},
+	{"list", "List the generator config files and the resources they configure", runList},
+	{"diff", "Report breaking changes between the generated schemas and their snapshots", runDiff},
+	{"clean", "Remove temporary files and generated files that are no longer produced", runClean},
+}
 
 func main() {
-	// setup slog with colour to make it easier to read
-	slog.SetDefault(slog.New(
-		tint.NewHandler(os.Stderr, &tint.Options{
-			Level:      slog.LevelDebug,
-			TimeFormat: time.Kitchen,
-		}),
-	))
-
-	generateFiles := []string{}
-	filepath.Walk("./", func(path string, info fs.FileInfo, err error) error {
-		filename := filepath.Base(path)
-		if configFilePattern.MatchString(filename) {
-			slog.Info("Found generator config file", "filename", filename)
-			generateFiles = append(generateFiles, path)
-		}
-		return nil
-	})
-
-	generatedResources := []generator.ResourceConfig{}
-	for _, f := range generateFiles {
-		config, err := generator.ParseHCLConfig(f)
-		if err != nil {
-			slog.Error("Error parsing configuration", "filename", f, "err", err)
-			os.Exit(1)
-		}
-		resources, err := generateFrameworkCode(f, config)
-		if err != nil {
-			slog.Error("Error generating framework code", "err", err)
-			os.Exit(1)
-		}
-		generatedResources = append(generatedResources, resources...)
+	args := os.Args[1:]
+	name := "generate"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
 
-	// generate resources list file
-	resourcesList := generator.ResourcesListGenerator{
-		time.Now(),
-		generatedResources,
-		generatePackageList(generatedResources),
+	for _, c := range commands {
+		if c.name == name {
+			os.Exit(c.run(args))
+		}
 	}
-	outputFilename := "resources_list_gen.go"
-	generator.WriteFormattedSourceFile("./provider", outputFilename, resourcesList.String())
-	slog.Info("Generated resources list source file", "filename", outputFilename)
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
 }
 
-func generatePackageList(resources []generator.ResourceConfig) []string {
-	packages := []string{}
-	packageMap := map[string]struct{}{}
-	for _, r := range resources {
-		packageMap[r.Package] = struct{}{}
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: tfplugingen-kubernetes [command] [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.description)
 	}
-	for k := range packageMap {
-		packages = append(packages, k)
-	}
-	return packages
+	fmt.Fprintf(os.Stderr, "\nRun tfplugingen-kubernetes [command] -help for the flags of each command.\n")
 }
 
-func generateFrameworkCode(path string, config generator.GeneratorConfig) ([]generator.ResourceConfig, error) {
-	wd := filepath.Dir(path)
+// stringSliceFlag is a flag that can be set multiple times
+type stringSliceFlag []string
 
-	generatedResources := []generator.ResourceConfig{}
-	for _, r := range config.Resources {
-		if r.Disabled {
-			slog.Warn("Code generation is disabled, skipping", "resource", r.Name)
-			continue
-		}
-		slog.Info("Generating framework code", "resource", r.Name)
-		spec, err := generator.GenerateResourceSpec(r)
-		if err != nil {
-			return nil, fmt.Errorf("error generating provider spec: %v", err)
-		}
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
 
-		gen := generator.NewResourceGenerator(r, spec)
+func (s *stringSliceFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
 
-		// generate resource
-		resourceCode := gen.GenerateResourceCode()
-		outputFilename := fmt.Sprintf("%s_gen.go", r.OutputFilenamePrefix)
-		generator.WriteFormattedSourceFile(wd, outputFilename, resourceCode)
-		slog.Info("Generated resource source file", "filename", outputFilename)
+// options contains the flags shared by the commands
+type options struct {
+	root            string
+	configFiles     stringSliceFlag
+	excludes        stringSliceFlag
+	providerDir     string
+	providerPackage string
+	logLevel        string
+	logFormat       string
+}
 
-		// generate schema
-		if r.Generate.Schema {
-			schemaCode := gen.GenerateSchemaFunctionCode()
-			outputFilename = fmt.Sprintf("%s_schema_gen.go", r.OutputFilenamePrefix)
-			generator.WriteFormattedSourceFile(wd, outputFilename, schemaCode)
-			slog.Info("Generated schema source file", "filename", outputFilename)
-		}
+func newFlagSet(name string) (*flag.FlagSet, *options) {
+	opts := &options{}
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.StringVar(&opts.root, "root", ".", "directory to search for generator config files")
+	flags.Var(&opts.configFiles, "config", "generator config file to use instead of searching the root directory, can be repeated")
+	flags.Var(&opts.excludes, "exclude", "glob of files or directories to skip when searching for config files, can be repeated")
+	flags.StringVar(&opts.logLevel, "log-level", "debug", "log level, one of debug, info, warn or error")
+	flags.StringVar(&opts.logFormat, "log-format", "pretty", "log format, one of pretty, text or json")
+	return flags, opts
+}
 
-		// generate CRUD stubs
-		if r.Generate.CRUDStubs {
-			crudStubCode := gen.GenerateCRUDStubCode()
-			outputFilename = fmt.Sprintf("%s_crud.go", r.OutputFilenamePrefix)
-			generator.WriteFormattedSourceFile(wd, outputFilename, crudStubCode)
-			slog.Info("Generated CRUD stub source file", "filename", outputFilename)
-		}
+// addProviderFlags adds the flags for the commands that write the provider code
+func (o *options) addProviderFlags(flags *flag.FlagSet) {
+	flags.StringVar(&o.providerDir, "provider-dir", "", "directory to write the provider resources list to, overrides output_dir in the provider block")
+	flags.StringVar(&o.providerPackage, "provider-package", "", "import path of the directory containing the resource packages, overrides module_path and package_root in the provider block for the resource imports")
+}
 
-		// generate auto CRUD functions
-		if r.Generate.CRUDAuto {
-			crudStubCode := gen.GenerateAutoCRUDCode()
-			outputFilename = fmt.Sprintf("%s_crud_gen.go", r.OutputFilenamePrefix)
-			generator.WriteFormattedSourceFile(wd, outputFilename, crudStubCode)
-			slog.Info("Generated autocrud source file", "filename", outputFilename)
-		}
+// newLogHandler returns a log handler configured from the log flags
+func (o *options) newLogHandler(w io.Writer) (slog.Handler, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(o.logLevel)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", o.logLevel)
+	}
 
-		if r.Generate.CRUDAutoOptions != nil {
-			if !r.Generate.CRUDAutoOptions.Hooks.IsEmpty() {
-				Hookscode := gen.GenerateAutoCRUDHooksCode()
-				outputFilename = fmt.Sprintf("%s_hooks.go", r.OutputFilenamePrefix)
-				if ok, _ := os.Stat(filepath.Join(wd, outputFilename)); ok == nil {
-					generator.WriteFormattedSourceFile(wd, outputFilename, Hookscode)
-					slog.Info("Generated autocrud hooks file", "filename", outputFilename)
-				} else {
-					slog.Warn("File already generated. Delete to regenerate", "filename", outputFilename)
-				}
-			}
-		}
+	switch o.logFormat {
+	case "pretty":
+		// setup slog with colour to make it easier to read
+		return tint.NewHandler(w, &tint.Options{
+			Level:      level,
+			TimeFormat: time.Kitchen,
+		}), nil
+	case "text":
+		return slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}), nil
+	case "json":
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q", o.logFormat)
+	}
+}
 
-		// generate model
-		if r.Generate.Model {
-			crudStubCode := gen.GenerateModelCode()
-			outputFilename = fmt.Sprintf("%s_model_gen.go", r.OutputFilenamePrefix)
-			generator.WriteFormattedSourceFile(wd, outputFilename, crudStubCode)
-			slog.Info("Generated model source file", "filename", outputFilename)
-		}
+// setupLogging configures the default logger from the log flags
+func (o *options) setupLogging() error {
+	handler, err := o.newLogHandler(os.Stderr)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
 
-		// generate genAI Validators
-		if r.Generate.GenAIValidation {
-			genAIValidatorsCode := gen.GenerateGenAIValidatorsCode()
-			outputFilename = fmt.Sprintf("%s_validators_gen.go", r.OutputFilenamePrefix)
-			generator.WriteFormattedSourceFile(wd, outputFilename, genAIValidatorsCode)
-			slog.Info("Generated genAI based validators file", "filename", outputFilename)
-		}
+// providerConfig resolves the provider configuration from the config files,
+// the provider flags take precedence when they are set
+func (o *options) providerConfig(configs []generator.GeneratorConfig) (generator.ProviderConfig, error) {
+	p, err := generator.ResolveProviderConfig(configs)
+	if err != nil {
+		return p, err
+	}
+	if o.providerDir != "" {
+		p.OutputDir = o.providerDir
+	}
+	if o.providerPackage != "" {
+		p.PackageImportPathOverride = o.providerPackage
+	}
+	return p, nil
+}
+
+// codegenTempDir returns the directory for temporary files, which is
+// relative to the root directory rather than the working directory
+func (o *options) codegenTempDir() string {
+	return filepath.Join(o.root, generator.CodegenTempDir)
+}
 
-		generatedResources = append(generatedResources, r)
+// parseFlags parses the flags for a command and sets up logging
+func parseFlags(flags *flag.FlagSet, opts *options, args []string) error {
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
 	}
-	return generatedResources, nil
+	return opts.setupLogging()
 }
@@ -0,0 +1,51 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+func TestProviderPackageFlag(t *testing.T) {
+	flags, opts := newFlagSet("generate")
+	opts.addProviderFlags(flags)
+	if err := flags.Parse([]string{"-provider-package", "example.com/fork/internal/resources"}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := opts.providerConfig([]generator.GeneratorConfig{
+		{Provider: &generator.ProviderConfig{ModulePath: "example.com/provider", ClientPackage: "client"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := generator.ResourceConfig{
+		Name:                 "kubernetes_widget_v1",
+		Package:              "widgetv1",
+		OutputFilenamePrefix: "widget",
+		APIVersion:           "example.com/v1",
+		Kind:                 "Widget",
+	}
+	gen := generator.NewResourceGenerator(p, r, specresource.Resource{Name: r.Name, Schema: &specresource.Schema{}})
+	code, err := gen.GenerateResourceCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(code, `"example.com/provider/client"`) {
+		t.Errorf("expected the client to be imported from the module path, got:\n%s", code)
+	}
+
+	list, err := generator.NewResourcesListGenerator([]generator.ResourceConfig{r}, p).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(list, `"example.com/fork/internal/resources/widgetv1"`) {
+		t.Errorf("expected the resource package to be imported from the flag, got:\n%s", list)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+// manifestFilename is the name of the manifest file written to the root directory
+const manifestFilename = ".tfplugingen-kubernetes.manifest.json"
+
+// manifest records the generated files produced for each config file so
+// that files which are no longer produced can be found and removed
+type manifest struct {
+	ConfigFiles map[string][]string `json:"config_files"`
+}
+
+func newManifest() manifest {
+	return manifest{ConfigFiles: map[string][]string{}}
+}
+
+// readManifest reads the manifest, returning an empty manifest if it does not exist
+func readManifest(filename string) (manifest, error) {
+	m := newManifest()
+	contents, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	} else if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(contents, &m); err != nil {
+		return m, fmt.Errorf("error parsing manifest %s: %v", filename, err)
+	}
+	if m.ConfigFiles == nil {
+		m.ConfigFiles = map[string][]string{}
+	}
+	return m, nil
+}
+
+func (m manifest) write(filename string) error {
+	contents, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(contents, '\n'), 0o644)
+}
+
+// add records the generated files produced for the resources in a config file
+func (m manifest) add(configFile string, config generator.GeneratorConfig) {
+	files := []string{}
+	for _, r := range config.Resources {
+		if r.Disabled {
+			continue
+		}
+		for _, f := range generatedFilenames(r) {
+			files = append(files, filepath.Join(filepath.Dir(configFile), f))
+		}
+	}
+	sort.Strings(files)
+	m.ConfigFiles[configFile] = files
+}
+
+// orphans returns the files in the previous manifest that are not in this one
+func (m manifest) orphans(previous manifest) []string {
+	current := map[string]bool{}
+	for _, files := range m.ConfigFiles {
+		for _, f := range files {
+			current[f] = true
+		}
+	}
+	orphans := []string{}
+	for _, files := range previous.ConfigFiles {
+		for _, f := range files {
+			if !current[f] {
+				orphans = append(orphans, f)
+			}
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// generatedFilenames returns the names of the files that generateResource
+// produces and overwrites on every run. Files that are only written once,
+// like the hooks and CRUD stubs, and the schema snapshot are not included.
+func generatedFilenames(r generator.ResourceConfig) []string {
+	files := []string{fmt.Sprintf("%s_gen.go", r.OutputFilenamePrefix)}
+	if r.Generate.Schema {
+		files = append(files, fmt.Sprintf("%s_schema_gen.go", r.OutputFilenamePrefix))
+	}
+	if len(r.StateUpgrades) > 0 {
+		files = append(files, fmt.Sprintf("%s_state_upgrade_gen.go", r.OutputFilenamePrefix))
+	}
+	if len(r.MoveFrom) > 0 {
+		files = append(files, fmt.Sprintf("%s_move_state_gen.go", r.OutputFilenamePrefix))
+	}
+	if r.Generate.CRUDAuto {
+		files = append(files, fmt.Sprintf("%s_crud_gen.go", r.OutputFilenamePrefix))
+	}
+	if r.Generate.Model {
+		files = append(files, fmt.Sprintf("%s_model_gen.go", r.OutputFilenamePrefix))
+	}
+	if r.Generate.CELValidation {
+		files = append(files, fmt.Sprintf("%s_cel_validation_gen.go", r.OutputFilenamePrefix))
+	}
+	if r.Generate.GenAIValidation {
+		files = append(files, fmt.Sprintf("%s_validators_gen.go", r.OutputFilenamePrefix))
+	}
+	if r.Generate.ValidatorTests {
+		files = append(files, fmt.Sprintf("%s_validators_gen_test.go", r.OutputFilenamePrefix))
+	}
+	return files
+}
+
+// pruneFiles removes generated files that are no longer produced. Files
+// without the generated code header are never removed. In check mode
+// nothing is removed, instead the files are recorded as stale.
+func pruneFiles(w *fileWriter, files []string) {
+	for _, f := range files {
+		contents, err := os.ReadFile(f)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			slog.Error("Error reading orphaned file", "filename", f, "err", err)
+			continue
+		}
+		if !generator.IsGeneratedSource(contents) {
+			slog.Warn("Not removing orphaned file without a generated code header", "filename", f)
+			continue
+		}
+		if w.check {
+			w.addStale(filepath.Dir(f), filepath.Base(f))
+			continue
+		}
+		if err := os.Remove(f); err != nil {
+			slog.Error("Error removing orphaned file", "filename", f, "err", err)
+			continue
+		}
+		slog.Info("Removed orphaned generated file", "filename", f)
+	}
+}
+
+// syncManifest updates the manifest with the files produced for the parsed
+// config files and writes it. The entries for the kept config files, those
+// that could not be parsed or were not selected in this run, are copied from
+// the previous manifest. Files that are no longer produced are removed when
+// prune is set, otherwise they are kept in the manifest so a later prune
+// can remove them. In check mode the manifest is not written.
+func syncManifest(w *fileWriter, root string, configFiles []string, configs []generator.GeneratorConfig, keptConfigFiles []string, prune bool) error {
+	manifestPath := filepath.Join(root, manifestFilename)
+	previous, err := readManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	m := newManifest()
+	for i, f := range configFiles {
+		m.add(f, configs[i])
+	}
+	for f, files := range previous.ConfigFiles {
+		if slices.ContainsFunc(keptConfigFiles, func(kept string) bool { return sameFile(kept, f) }) {
+			m.ConfigFiles[f] = files
+		}
+	}
+
+	orphans := m.orphans(previous)
+	if prune {
+		pruneFiles(w, orphans)
+	} else {
+		for _, f := range orphans {
+			slog.Warn("Generated file is no longer produced, use -prune to remove it", "filename", f)
+		}
+		m.keep(previous, orphans)
+	}
+
+	if w.check {
+		return nil
+	}
+	return m.write(manifestPath)
+}
+
+// keep adds the files to the manifest under the config files they were
+// recorded for in the previous manifest
+func (m manifest) keep(previous manifest, files []string) {
+	keep := map[string]bool{}
+	for _, f := range files {
+		keep[f] = true
+	}
+	for configFile, previousFiles := range previous.ConfigFiles {
+		for _, f := range previousFiles {
+			if keep[f] {
+				m.ConfigFiles[configFile] = append(m.ConfigFiles[configFile], f)
+			}
+		}
+		if files, ok := m.ConfigFiles[configFile]; ok {
+			sort.Strings(files)
+		}
+	}
+}
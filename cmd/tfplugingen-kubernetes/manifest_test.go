@@ -0,0 +1,64 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+func TestSyncManifestSelectedConfig(t *testing.T) {
+	root := t.TempDir()
+	a := writeTestConfig(t, filepath.Join(root, "a"), "kubernetes_a", "a")
+	b := writeTestConfig(t, filepath.Join(root, "b"), "kubernetes_b", "b")
+
+	// a used to generate files with the old_a prefix, which are orphans
+	generated := []byte("// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.\n\npackage a\n")
+	files := map[string][]string{
+		a: {filepath.Join(root, "a", "a_gen.go"), filepath.Join(root, "a", "old_a_gen.go")},
+		b: {filepath.Join(root, "b", "b_gen.go"), filepath.Join(root, "b", "b_schema_gen.go")},
+	}
+	for _, fs := range files {
+		for _, f := range fs {
+			if err := os.WriteFile(f, generated, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := (manifest{ConfigFiles: files}).write(filepath.Join(root, manifestFilename)); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &options{root: root, configFiles: stringSliceFlag{a}}
+	config, err := opts.parseConfig(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unselected, err := opts.unselectedConfigFiles([]string{a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := syncManifest(&fileWriter{}, root, []string{a}, []generator.GeneratorConfig{config}, unselected, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(files[a][1]); err == nil {
+		t.Errorf("expected the orphaned file of the selected config to be removed")
+	}
+	for _, f := range append(files[b], files[a][0]) {
+		if _, err := os.Stat(f); err != nil {
+			t.Errorf("expected %s to be kept: %v", f, err)
+		}
+	}
+	m, err := readManifest(filepath.Join(root, manifestFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.ConfigFiles[b]) != 2 {
+		t.Errorf("expected the manifest entry for the unselected config to be kept got %v", m.ConfigFiles)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+// generateJob is a resource to generate code for
+type generateJob struct {
+	configFile string
+	resource   generator.ResourceConfig
+
+	// hash is the hash of the inputs for the resource
+	hash string
+
+	// unchanged is true when the inputs have not changed since the last run
+	unchanged bool
+}
+
+// resourcesGenerator generates the code for resources concurrently
+type resourcesGenerator struct {
+	writer     *fileWriter
+	provider   generator.ProviderConfig
+	specs      *generator.ResourceSpecCache
+	opts       *options
+	validators generator.ValidatorGenerator
+
+	validatorOptions validatorOptions
+}
+
+// validatorOptions configures how genAI validators are generated
+type validatorOptions struct {
+	model   string
+	refresh bool
+	review  bool
+}
+
+// generate runs the jobs using a pool of workers and returns the results in
+// the same order as the jobs. The log output for each resource is buffered
+// and written once the resource and all of the ones before it have finished,
+// so the logs are grouped by resource and in the same order on every run.
+// A failure generating one resource does not stop the others from being generated.
+func (g *resourcesGenerator) generate(jobs []generateJob, parallel int) []result {
+	results := make([]result, len(jobs))
+	logs := make([]bytes.Buffer, len(jobs))
+	done := make([]chan struct{}, len(jobs))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < parallel; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = g.run(jobs[i], &logs[i])
+				close(done[i])
+			}
+		}()
+	}
+	go func() {
+		for i := range jobs {
+			queue <- i
+		}
+		close(queue)
+	}()
+
+	for i := range jobs {
+		<-done[i]
+		io.Copy(os.Stderr, &logs[i])
+	}
+	wg.Wait()
+	return results
+}
+
+// run generates the code for a single resource, writing its logs to w
+func (g *resourcesGenerator) run(job generateJob, w io.Writer) result {
+	r := job.resource
+	handler, err := g.opts.newLogHandler(w)
+	if err != nil {
+		return result{ConfigFile: job.configFile, Resource: r.Name, Status: statusFailed, Err: err}
+	}
+	log := slog.New(handler)
+
+	if r.Disabled {
+		log.Warn("Code generation is disabled, skipping", "resource", r.Name)
+		return result{ConfigFile: job.configFile, Resource: r.Name, Status: statusDisabled}
+	}
+
+	if job.unchanged {
+		log.Info("Inputs unchanged since the last run, skipping", "resource", r.Name)
+		return result{ConfigFile: job.configFile, Resource: r.Name, Status: statusUnchanged}
+	}
+
+	log.Info("Generating framework code", "resource", r.Name)
+	err = g.generateResource(log, filepath.Dir(job.configFile), r)
+	if err != nil {
+		log.Error("Error generating framework code", "resource", r.Name, "err", err)
+		return result{ConfigFile: job.configFile, Resource: r.Name, Status: statusFailed, Err: err}
+	}
+	return result{ConfigFile: job.configFile, Resource: r.Name, Status: statusOK}
+}
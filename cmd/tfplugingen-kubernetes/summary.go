@@ -0,0 +1,49 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+const (
+	statusOK        = "ok"
+	statusFailed    = "failed"
+	statusDisabled  = "disabled"
+	statusUnchanged = "unchanged"
+)
+
+// result is the outcome of generating the code for a resource
+type result struct {
+	ConfigFile string
+	Resource   string
+	Status     string
+	Err        error
+}
+
+// printSummary prints a table with the result of generating each resource
+func printSummary(out io.Writer, results []result) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONFIG\tRESOURCE\tSTATUS\tERROR")
+	for _, r := range results {
+		errMsg := ""
+		if r.Err != nil {
+			errMsg = r.Err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.ConfigFile, r.Resource, r.Status, errMsg)
+	}
+	tw.Flush()
+}
+
+func countFailed(results []result) int {
+	failed := 0
+	for _, r := range results {
+		if r.Status == statusFailed {
+			failed++
+		}
+	}
+	return failed
+}
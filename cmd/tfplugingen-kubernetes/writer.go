@@ -0,0 +1,60 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator"
+)
+
+// fileWriter writes generated files to disk. In check mode nothing is
+// written, instead the generated files are compared with the files on
+// disk and the stale ones are recorded. It is safe for concurrent use.
+type fileWriter struct {
+	check bool
+
+	mu    sync.Mutex
+	stale []string
+}
+
+func (w *fileWriter) addStale(wd, filename string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stale = append(w.stale, filepath.Join(wd, filename))
+}
+
+// WriteSourceFile formats and writes a generated Go source file, the policy
+// controls what happens when the file already exists. It returns false if
+// the existing file was left unchanged.
+func (w *fileWriter) WriteSourceFile(wd, filename, contents string, policy generator.WritePolicy) (bool, error) {
+	if !w.check {
+		return generator.WriteFormattedSourceFile(wd, filename, contents, policy)
+	}
+	ok, err := generator.CheckFormattedSourceFile(wd, filename, contents, policy)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		w.addStale(wd, filename)
+	}
+	return false, nil
+}
+
+// WriteFile writes a generated file that is not Go source
+func (w *fileWriter) WriteFile(wd, filename string, contents []byte) error {
+	if !w.check {
+		return os.WriteFile(filepath.Join(wd, filename), contents, os.ModePerm)
+	}
+	ok, err := generator.CheckFile(wd, filename, contents)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		w.addStale(wd, filename)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+)
+
+const frameworkValidatorsImportPath = "github.com/hashicorp/terraform-plugin-framework-validators"
+
+// knownPackages are the packages that are imported automatically when they
+// are used in the validators and plan modifiers of an attribute block
+var knownPackages = map[string]string{
+	"boolvalidator":       path.Join(frameworkValidatorsImportPath, "boolvalidator"),
+	"stringvalidator":     path.Join(frameworkValidatorsImportPath, "stringvalidator"),
+	"numbervalidator":     path.Join(frameworkValidatorsImportPath, "numbervalidator"),
+	"int64validator":      path.Join(frameworkValidatorsImportPath, "int64validator"),
+	"float64validator":    path.Join(frameworkValidatorsImportPath, "float64validator"),
+	"listvalidator":       path.Join(frameworkValidatorsImportPath, "listvalidator"),
+	"setvalidator":        path.Join(frameworkValidatorsImportPath, "setvalidator"),
+	"mapvalidator":        path.Join(frameworkValidatorsImportPath, "mapvalidator"),
+	"objectvalidator":     path.Join(frameworkValidatorsImportPath, "objectvalidator"),
+	"boolplanmodifier":    path.Join(schemaImportPath, "boolplanmodifier"),
+	"stringplanmodifier":  path.Join(schemaImportPath, "stringplanmodifier"),
+	"numberplanmodifier":  path.Join(schemaImportPath, "numberplanmodifier"),
+	"int64planmodifier":   path.Join(schemaImportPath, "int64planmodifier"),
+	"float64planmodifier": path.Join(schemaImportPath, "float64planmodifier"),
+	"listplanmodifier":    path.Join(schemaImportPath, "listplanmodifier"),
+	"setplanmodifier":     path.Join(schemaImportPath, "setplanmodifier"),
+	"mapplanmodifier":     path.Join(schemaImportPath, "mapplanmodifier"),
+	"objectplanmodifier":  path.Join(schemaImportPath, "objectplanmodifier"),
+	"validator":           validatorImportPath,
+	"planmodifier":        path.Join(schemaImportPath, "planmodifier"),
+	"path":                "github.com/hashicorp/terraform-plugin-framework/path",
+	"regexp":              "regexp",
+}
+
+var attributeNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// attributeConfig returns the options for the attribute at path, merged
+// from every attribute block that matches it in order
+func (r ResourceConfig) attributeConfig(attributePath string) AttributeConfig {
+	merged := AttributeConfig{Path: attributePath}
+	for _, a := range r.Attributes {
+		if !matchPathPattern(a.Path, attributePath) {
+			continue
+		}
+		if a.Description != nil {
+			merged.Description = a.Description
+		}
+		if a.Default != nil {
+			merged.Default = a.Default
+		}
+		if a.Ignored != nil {
+			merged.Ignored = a.Ignored
+		}
+		if a.Required != nil {
+			merged.Required = a.Required
+		}
+		if a.Computed != nil {
+			merged.Computed = a.Computed
+		}
+		if a.Sensitive != nil {
+			merged.Sensitive = a.Sensitive
+		}
+		if a.Immutable != nil {
+			merged.Immutable = a.Immutable
+		}
+		if a.DeprecationMessage != "" {
+			merged.DeprecationMessage = a.DeprecationMessage
+		}
+		if a.Rename != "" {
+			merged.Rename = a.Rename
+		}
+		merged.Validators = append(merged.Validators, a.Validators...)
+		merged.PlanModifiers = append(merged.PlanModifiers, a.PlanModifiers...)
+		merged.Imports = append(merged.Imports, a.Imports...)
+	}
+	return merged
+}
+
+// IsIgnored returns true if the attribute is omitted from the resource
+func (a AttributeConfig) IsIgnored() bool {
+	return a.Ignored != nil && *a.Ignored
+}
+
+// IsRequired returns true if the attribute is required, ValidateAttributes
+// checks that required attributes don't have a default
+func (a AttributeConfig) IsRequired() bool {
+	return a.Required != nil && *a.Required
+}
+
+// IsComputed returns true if the attribute is computed, attributes with a default are always computed
+func (a AttributeConfig) IsComputed() bool {
+	return (a.Computed != nil && *a.Computed) || a.Default != nil
+}
+
+// IsSensitive returns true if the attribute is sensitive
+func (a AttributeConfig) IsSensitive() bool {
+	return a.Sensitive != nil && *a.Sensitive
+}
+
+// IsImmutable returns true if changing the attribute forces a replacement
+func (a AttributeConfig) IsImmutable() bool {
+	return a.Immutable != nil && *a.Immutable
+}
+
+// Name returns the name of the attribute in the schema
+func (a AttributeConfig) Name(name string) string {
+	if a.Rename != "" {
+		return a.Rename
+	}
+	return name
+}
+
+// schemaPath returns the path in the schema and the path in the manifest of
+// the attribute at specPath, taking renamed attributes into account. It
+// returns false if the attribute or one of its parents is ignored.
+func (r ResourceConfig) schemaPath(specPath string) (string, string, bool) {
+	if specPath == "" {
+		return "", "", true
+	}
+	attributePath, manifestPath := []string{}, []string{}
+	prefix := ""
+	for _, segment := range strings.Split(specPath, ".") {
+		name, suffix := segment, ""
+		if n, ok := strings.CutSuffix(segment, "[*]"); ok {
+			name, suffix = n, "[*]"
+		}
+		a := r.attributeConfig(prefix + name)
+		if a.IsIgnored() {
+			return "", "", false
+		}
+		attributePath = append(attributePath, a.Name(name)+suffix)
+		manifestPath = append(manifestPath, MapTerraformAttributeToKubernetes(name)+suffix)
+		prefix += segment + "."
+	}
+	return strings.Join(attributePath, "."), strings.Join(manifestPath, "."), true
+}
+
+// imports returns the import paths needed by the validators and plan
+// modifiers, which are the known packages referred to anywhere in them
+func (a AttributeConfig) imports() []string {
+	imports := append([]string{}, a.Imports...)
+	for _, expr := range append(append([]string{}, a.Validators...), a.PlanModifiers...) {
+		e, err := parser.ParseExpr(expr)
+		if err != nil {
+			// the expression is copied into the schema as it is, so the
+			// error is reported when the generated code is built
+			continue
+		}
+		ast.Inspect(e, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+					if importPath, ok := knownPackages[id.Name]; ok {
+						imports = append(imports, importPath)
+					}
+				}
+			}
+			return true
+		})
+	}
+	return imports
+}
+
+// defaultValue returns the Go expression for the default value of the
+// attribute and the packages it needs to import
+func defaultValue(attr specresource.Attribute, value string) (string, []string, error) {
+	switch {
+	case attr.Bool != nil:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("default %q is not a bool", value)
+		}
+		return fmt.Sprintf("booldefault.StaticBool(%t)", v), []string{path.Join(schemaImportPath, "booldefault")}, nil
+	case attr.String != nil:
+		return fmt.Sprintf("stringdefault.StaticString(%q)", value), []string{path.Join(schemaImportPath, "stringdefault")}, nil
+	case attr.Int64 != nil:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("default %q is not an int64", value)
+		}
+		return fmt.Sprintf("int64default.StaticInt64(%d)", v), []string{path.Join(schemaImportPath, "int64default")}, nil
+	case attr.Number != nil:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("default %q is not a number", value)
+		}
+		return fmt.Sprintf("numberdefault.StaticBigFloat(big.NewFloat(%s))", strconv.FormatFloat(v, 'g', -1, 64)), []string{path.Join(schemaImportPath, "numberdefault"), "math/big"}, nil
+	}
+	return "", nil, fmt.Errorf("default is only supported for bool, string, number and int64 attributes")
+}
+
+// specDefault returns the static default value for the attribute from the
+// OpenAPI spec. Kubernetes publishes the zero value as the default for the
+// fields of built-in types that are not omitempty, so for built-in types zero
+// values are not treated as defaults. Defaults in CRD schemas are applied by
+// the API server whatever their value.
+func specDefault(attr specresource.Attribute, builtin bool) (string, bool) {
+	switch {
+	case attr.Bool != nil && attr.Bool.Default != nil && attr.Bool.Default.Static != nil:
+		return strconv.FormatBool(*attr.Bool.Default.Static), !builtin || *attr.Bool.Default.Static
+	case attr.String != nil && attr.String.Default != nil && attr.String.Default.Static != nil:
+		return *attr.String.Default.Static, !builtin || *attr.String.Default.Static != ""
+	case attr.Int64 != nil && attr.Int64.Default != nil && attr.Int64.Default.Static != nil:
+		return strconv.FormatInt(*attr.Int64.Default.Static, 10), !builtin || *attr.Int64.Default.Static != 0
+	}
+	return "", false
+}
+
+// isBuiltinType returns true if the resource is a built-in Kubernetes type
+// rather than a custom resource. Built-in types are in the core group, a
+// group without a domain such as apps, or a k8s.io group.
+func (r ResourceConfig) isBuiltinType() bool {
+	group, _, ok := strings.Cut(r.APIVersion, "/")
+	if !ok {
+		return true
+	}
+	return !strings.Contains(group, ".") || (strings.HasSuffix(group, ".k8s.io") && !strings.HasSuffix(group, ".x-k8s.io"))
+}
+
+func validateAttributes(r ResourceConfig) error {
+	seen := map[string]bool{"id": true, "timeouts": true}
+	for _, name := range r.CustomAttributes {
+		if !attributeNameRegexp.MatchString(name) {
+			return fmt.Errorf("custom attribute %q for %s is not valid, the name must be lowercase letters, digits and underscores", name, r.Name)
+		}
+		if seen[name] {
+			return fmt.Errorf("custom attribute %q for %s is already declared", name, r.Name)
+		}
+		seen[name] = true
+	}
+	for _, a := range r.Attributes {
+		if a.Rename == "" {
+			continue
+		}
+		if isPathPattern(a.Path) {
+			return fmt.Errorf("attribute %q for %s cannot be renamed because it is a pattern", a.Path, r.Name)
+		}
+		if !attributeNameRegexp.MatchString(a.Rename) {
+			return fmt.Errorf("attribute %q for %s cannot be renamed to %q, the name must be lowercase letters, digits and underscores", a.Path, r.Name, a.Rename)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAttributeConfigMerge(t *testing.T) {
+	cfg := ResourceConfig{
+		Attributes: []AttributeConfig{
+			{Path: "**.name", Immutable: ptr(true), Validators: []string{"stringvalidator.LengthAtLeast(1)"}},
+			{Path: "spec.ports[*].name", Immutable: ptr(false), Rename: "port_name"},
+		},
+	}
+
+	a := cfg.attributeConfig("metadata.name")
+	if !a.IsImmutable() || a.Name("name") != "name" {
+		t.Fatalf("expected metadata.name to be immutable and not renamed got %+v", a)
+	}
+
+	a = cfg.attributeConfig("spec.ports[*].name")
+	if a.IsImmutable() {
+		t.Fatalf("expected later block to make spec.ports[*].name mutable")
+	}
+	if a.Name("name") != "port_name" {
+		t.Fatalf("expected %q got %q", "port_name", a.Name("name"))
+	}
+	if len(a.Validators) != 1 {
+		t.Fatalf("expected validators from the pattern block got %v", a.Validators)
+	}
+	expected := frameworkValidatorsImportPath + "/stringvalidator"
+	if imports := a.imports(); len(imports) != 1 || imports[0] != expected {
+		t.Fatalf("expected %q got %v", expected, imports)
+	}
+}
+
+func TestAttributeConfigImports(t *testing.T) {
+	a := AttributeConfig{
+		Validators: []string{
+			`listvalidator.ValueStringsAre(stringvalidator.OneOf("a"))`,
+			`setvalidator.SizeAtLeast(1)`,
+		},
+		PlanModifiers: []string{"float64planmodifier.UseStateForUnknown()"},
+	}
+	imports := a.imports()
+	for _, expected := range []string{
+		frameworkValidatorsImportPath + "/listvalidator",
+		frameworkValidatorsImportPath + "/stringvalidator",
+		frameworkValidatorsImportPath + "/setvalidator",
+		schemaImportPath + "/float64planmodifier",
+	} {
+		if !slices.Contains(imports, expected) {
+			t.Errorf("expected %q in %v", expected, imports)
+		}
+	}
+}
+
+func TestAttributeConfigOverrides(t *testing.T) {
+	cfg := testResourceConfig(t)
+	cfg.Attributes = append(cfg.Attributes, AttributeConfig{
+		Path:               "spec.replicas",
+		Description:        ptr("Number of replicas."),
+		Default:            ptr("1"),
+		DeprecationMessage: "Use replica_count instead.",
+		Rename:             "replica_count",
+	})
+	code := generateAllCode(t, cfg)
+
+	for _, expected := range []string{
+		`"replica_count": schema.Int64Attribute{`,
+		"MarkdownDescription: `Number of replicas.`,",
+		`DeprecationMessage: "Use replica_count instead.",`,
+		`Default: int64default.StaticInt64(1),`,
+		`"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"`,
+	} {
+		if !strings.Contains(code["schema"], expected) {
+			t.Errorf("expected schema to contain %q", expected)
+		}
+	}
+	expected := "ReplicaCount types.Int64 `tfsdk:\"replica_count\" manifest:\"replicas\"`"
+	if !strings.Contains(code["model"], expected) {
+		t.Errorf("expected model to contain %q", expected)
+	}
+}
+
+func TestSpecDefaults(t *testing.T) {
+	cfg := testResourceConfig(t)
+	code := generateAllCode(t, cfg)
+	expected := `Default: stringdefault.StaticString("TCP"),`
+	if !strings.Contains(code["schema"], expected) {
+		t.Fatalf("expected schema to contain %q", expected)
+	}
+	if !strings.Contains(code["schema"], `stringdefault.StaticString("")`) {
+		t.Fatalf("expected zero value default in a CRD schema to be used")
+	}
+
+	builtin := cfg
+	builtin.APIVersion = "apps/v1"
+	code = generateAllCode(t, builtin)
+	if strings.Contains(code["schema"], `stringdefault.StaticString("")`) {
+		t.Fatalf("expected zero value default for a built-in type to be ignored")
+	}
+
+	cfg.Attributes = append(cfg.Attributes, AttributeConfig{Path: "spec.ports[*].protocol", Default: ptr("UDP")})
+	code = generateAllCode(t, cfg)
+	expected = `Default: stringdefault.StaticString("UDP"),`
+	if !strings.Contains(code["schema"], expected) {
+		t.Fatalf("expected schema to contain %q", expected)
+	}
+}
+
+func TestValidateCustomAttributes(t *testing.T) {
+	for _, names := range [][]string{{"Manifest"}, {"object", "object"}, {"id"}} {
+		if err := validateAttributes(ResourceConfig{Name: "kubernetes_widget_v1", CustomAttributes: names}); err == nil {
+			t.Errorf("expected error for custom attributes %q", names)
+		}
+	}
+	if err := validateAttributes(ResourceConfig{Name: "kubernetes_widget_v1", CustomAttributes: []string{"object"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+)
+
+// patternMatchesAny returns true if the pattern matches any of the attribute paths
+func patternMatchesAny(pattern string, paths []string) bool {
+	for _, p := range paths {
+		if matchPathPattern(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathPattern returns true if the attribute path matches the pattern.
+// Paths are made up of segments separated by a "." and list nested attributes
+// are written as list[*]. In a pattern "*" matches any single segment and "**"
+// matches any number of segments, e.g. "**.status" or "spec.*.resources".
+func matchPathPattern(pattern, attributePath string) bool {
+	if pattern == attributePath {
+		return true
+	}
+	if !isPathPattern(pattern) {
+		return false
+	}
+	return matchSegments(strings.Split(pattern, "."), strings.Split(attributePath, "."))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	switch pattern[0] {
+	case "**":
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(segments) > 0 && matchSegments(pattern[1:], segments[1:])
+	}
+	return len(segments) > 0 && pattern[0] == segments[0] && matchSegments(pattern[1:], segments[1:])
+}
+
+// isPathPattern returns true if the path contains a "*" or "**" segment
+func isPathPattern(p string) bool {
+	for _, s := range strings.Split(p, ".") {
+		if s == "*" || s == "**" {
+			return true
+		}
+	}
+	return false
+}
+
+// walkSpecAttributes calls fn with the path of every attribute in the spec.
+// The elements of list, set and map nested attributes are written as name[*].
+func walkSpecAttributes(attrs specresource.Attributes, path string, fn func(string, specresource.Attribute)) {
+	for _, attr := range attrs {
+		attributePath := path + attr.Name
+		fn(attributePath, attr)
+		switch {
+		case attr.SingleNested != nil:
+			walkSpecAttributes(attr.SingleNested.Attributes, attributePath+".", fn)
+		case attr.ListNested != nil:
+			walkSpecAttributes(attr.ListNested.NestedObject.Attributes, attributePath+"[*].", fn)
+		case attr.SetNested != nil:
+			walkSpecAttributes(attr.SetNested.NestedObject.Attributes, attributePath+"[*].", fn)
+		case attr.MapNested != nil:
+			walkSpecAttributes(attr.MapNested.NestedObject.Attributes, attributePath+"[*].", fn)
+		}
+	}
+}
+
+// specAttributePaths returns the path of every attribute in the spec
+func specAttributePaths(attrs specresource.Attributes) []string {
+	paths := []string{}
+	walkSpecAttributes(attrs, "", func(p string, _ specresource.Attribute) {
+		paths = append(paths, p)
+	})
+	return paths
+}
+
+// ValidateAttributes checks that the path of every attribute block in the
+// resource configuration matches at least one attribute in the spec, so that
+// typos are not silently ignored, that custom attributes do not have the same
+// name as an attribute in the spec, that defaults suit the attribute type and
+// that required attributes do not have a default
+func ValidateAttributes(cfg ResourceConfig, spec specresource.Resource) error {
+	paths := specAttributePaths(spec.Schema.Attributes)
+
+	var errs []error
+	for _, a := range cfg.Attributes {
+		if patternMatchesAny(a.Path, paths) {
+			continue
+		}
+		err := fmt.Errorf("attribute %q does not match any attribute", a.Path)
+		if suggestion := suggestPath(a.Path, paths); suggestion != "" {
+			err = fmt.Errorf("attribute %q does not match any attribute, did you mean %q?", a.Path, suggestion)
+		}
+		errs = append(errs, err)
+	}
+
+	for _, name := range cfg.CustomAttributes {
+		for _, attr := range spec.Schema.Attributes {
+			if a := cfg.attributeConfig(attr.Name); !a.IsIgnored() && a.Name(attr.Name) == name {
+				errs = append(errs, fmt.Errorf("custom attribute %q has the same name as an attribute in the spec", name))
+			}
+		}
+	}
+
+	walkSpecAttributes(spec.Schema.Attributes, "", func(p string, attr specresource.Attribute) {
+		a := cfg.attributeConfig(p)
+		if a.IsIgnored() {
+			return
+		}
+		if a.Default != nil {
+			if _, _, err := defaultValue(attr, *a.Default); err != nil {
+				errs = append(errs, fmt.Errorf("attribute %q: %v", p, err))
+			}
+		}
+		if !a.IsRequired() {
+			return
+		}
+		if a.Default != nil {
+			errs = append(errs, fmt.Errorf("attribute %q is required and has a default, an attribute with a default is always optional", p))
+		} else if _, ok := specDefault(attr, cfg.isBuiltinType()); ok {
+			errs = append(errs, fmt.Errorf("attribute %q is required but has a default in the OpenAPI spec, an attribute with a default is always optional", p))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// suggestPath returns the path closest to p, or an empty string if none of
+// them are close enough to be a likely typo
+func suggestPath(p string, paths []string) string {
+	if isPathPattern(p) {
+		return ""
+	}
+	suggestion := ""
+	best := len(p)/3 + 1
+	for _, candidate := range paths {
+		if d := levenshtein(p, candidate); d < best {
+			best = d
+			suggestion = candidate
+		}
+	}
+	return suggestion
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,109 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+)
+
+func TestMatchPathPattern(t *testing.T) {
+	testCases := []struct {
+		path     string
+		pattern  string
+		expected bool
+	}{
+		{"metadata.labels", "metadata.labels", true},
+		{"metadata.labels", "metadata.lables", false},
+		{"spec.ports[*].name", "spec.ports[*].name", true},
+		{"spec.ports[*].name", "spec.*.name", true},
+		{"spec.ports[*].name", "*.name", false},
+		{"status", "**.status", true},
+		{"spec.template.status", "**.status", true},
+		{"spec.template.status.phase", "**.status", false},
+		{"spec.containers[*].resources", "spec.*.resources", true},
+		{"spec.containers[*].env[*].resources", "spec.**.resources", true},
+	}
+	for _, tc := range testCases {
+		if actual := matchPathPattern(tc.pattern, tc.path); actual != tc.expected {
+			t.Errorf("expected %q matching %q to be %v", tc.pattern, tc.path, tc.expected)
+		}
+	}
+}
+
+func TestValidateAttributePaths(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := testResourceConfig(t)
+	if err := ValidateAttributes(cfg, spec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Attributes = []AttributeConfig{
+		{Path: "metadata.lables"},
+		{Path: "**.uid"},
+		{Path: "spec.ports.name"},
+		{Path: "**.secret"},
+		{Path: "spec.replicas", Default: ptr("one")},
+		{Path: "spec.args", Default: ptr("a")},
+		{Path: "spec.ports[*].protocol", Required: ptr(true)},
+		{Path: "spec.ports[*].container_port", Required: ptr(true), Default: ptr("80")},
+	}
+	err = ValidateAttributes(cfg, spec)
+	if err == nil {
+		t.Fatalf("expected error for invalid attribute paths")
+	}
+	for _, expected := range []string{
+		`attribute "metadata.lables" does not match any attribute, did you mean "metadata.labels"?`,
+		`attribute "spec.ports.name" does not match any attribute, did you mean "spec.ports[*].name"?`,
+		`attribute "**.secret" does not match any attribute`,
+		`attribute "spec.replicas": default "one" is not an int64`,
+		`attribute "spec.args": default is only supported for bool, string, number and int64 attributes`,
+		`attribute "spec.ports[*].protocol" is required but has a default in the OpenAPI spec`,
+		`attribute "spec.ports[*].container_port" is required and has a default`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in error got %q", expected, err.Error())
+		}
+	}
+	if strings.Contains(err.Error(), "uid") {
+		t.Errorf("expected **.uid to match got %q", err.Error())
+	}
+}
+
+func TestValidateNestedAttributePaths(t *testing.T) {
+	nested := specresource.NestedAttributeObject{
+		Attributes: specresource.Attributes{{Name: "value", String: &specresource.StringAttribute{}}},
+	}
+	spec := specresource.Resource{Schema: &specresource.Schema{
+		Attributes: specresource.Attributes{
+			{Name: "selectors", MapNested: &specresource.MapNestedAttribute{NestedObject: nested}},
+			{Name: "rules", SetNested: &specresource.SetNestedAttribute{NestedObject: nested}},
+		},
+	}}
+
+	cfg := ResourceConfig{
+		Name: "kubernetes_widget_v1",
+		Attributes: []AttributeConfig{
+			{Path: "selectors[*].value", Immutable: ptr(true)},
+			{Path: "rules[*].value", Immutable: ptr(true)},
+		},
+		CustomAttributes: []string{"manifest"},
+	}
+	if err := ValidateAttributes(cfg, spec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.CustomAttributes = []string{"rules"}
+	err := ValidateAttributes(cfg, spec)
+	expected := `custom attribute "rules" has the same name as an attribute in the spec`
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("expected %q in error got %v", expected, err)
+	}
+}
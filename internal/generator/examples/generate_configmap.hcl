@@ -18,6 +18,14 @@ resource "kubernetes_config_map_v1" {
     read_path   = "/api/v1/namespaces/{namespace}/configmaps/{name}"
   }
 
+  move_from "kubernetes_config_map" {
+    move {
+      from   = "metadata"
+      to     = "metadata"
+      unwrap = true
+    }
+  }
+
   generate {
     schema     = true
     model      = true
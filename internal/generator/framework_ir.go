@@ -15,7 +15,8 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
-var codegenTempDir = "./.codegen-tmp"
+// CodegenTempDir is the directory used for the temporary files passed to tfplugingen-openapi
+var CodegenTempDir = "./.codegen-tmp"
 
 var tfplugingenOpenAPIBinary = "tfplugingen-openapi"
 
@@ -23,52 +24,69 @@ var tfplugingenOpenAPIBinary = "tfplugingen-openapi"
 // framework IR JSON from an OpenAPI spec then marshalls the IR into
 // a spec.Resource
 func GenerateResourceSpec(r ResourceConfig) (specresource.Resource, error) {
-	// run tfplugingen-openapi to generate the framework IR for the resource
+	filename := r.OpenAPIConfig.Filename
+	specs, err := generateResourceSpecs(filename, readValidatorsOpenAPIDocument(filename), []ResourceConfig{r})
+	if err != nil {
+		return specresource.Resource{}, err
+	}
+	return specs[r.Name], nil
+}
+
+// generateResourceSpecs runs tfplugingen-openapi once to generate the
+// framework IR for all of the supplied resources, which must use the
+// same OpenAPI spec, and adds the validators from doc when it is not nil
+func generateResourceSpecs(openAPIFilename string, doc *OpenAPIDocument, resources []ResourceConfig) (map[string]specresource.Resource, error) {
+	// run tfplugingen-openapi to generate the framework IR for the resources
 	// TODO should codify this as a struct when the tool is out of preview
+	resourcesConfig := map[string]any{}
+	for _, r := range resources {
+		resourcesConfig[r.Name] = map[string]any{
+			"create": map[string]any{
+				"path":   r.OpenAPIConfig.CreatePath,
+				"method": "POST",
+			},
+			"read": map[string]any{
+				"path":   r.OpenAPIConfig.ReadPath,
+				"method": "GET",
+			},
+		}
+	}
 	tfpluginOpenAPIConfig := map[string]any{
 		"provider": map[string]any{
 			"name": "kubernetes",
 		},
-		"resources": map[string]any{
-			r.Name: map[string]any{
-				"create": map[string]any{
-					"path":   r.OpenAPIConfig.CreatePath,
-					"method": "POST",
-				},
-				"read": map[string]any{
-					"path":   r.OpenAPIConfig.ReadPath,
-					"method": "GET",
-				},
-			},
-		},
+		"resources": resourcesConfig,
 	}
 	yamlConfig, err := yaml.Marshal(tfpluginOpenAPIConfig)
 	if err != nil {
-		return specresource.Resource{}, fmt.Errorf("error marshalling tfplugingen-openapi configuration: %v", err)
+		return nil, fmt.Errorf("error marshalling tfplugingen-openapi configuration: %v", err)
 	}
 
 	tfplugingenopenapiPath, err := exec.LookPath(tfplugingenOpenAPIBinary)
 	if err != nil {
-		return specresource.Resource{}, fmt.Errorf(`could not find "tfplugingen-openapi" in PATH`)
+		return nil, fmt.Errorf(`could not find "tfplugingen-openapi" in PATH`)
 	}
 
-	os.Mkdir(codegenTempDir, os.ModePerm)
+	os.Mkdir(CodegenTempDir, os.ModePerm)
 
-	yamlConfigFile, err := os.CreateTemp(codegenTempDir, "terraform-codegen-*.yaml")
+	yamlConfigFile, err := os.CreateTemp(CodegenTempDir, "terraform-codegen-*.yaml")
 	defer func() {
 		yamlConfigFile.Close()
 	}()
 	if err != nil {
-		return specresource.Resource{}, fmt.Errorf("error creating temp file: %v", err)
+		return nil, fmt.Errorf("error creating temp file: %v", err)
+	}
+	_, err = yamlConfigFile.WriteString(string(yamlConfig))
+	if err != nil {
+		return nil, fmt.Errorf("error writing temp file: %v", err)
 	}
-	yamlConfigFile.WriteString(string(yamlConfig))
 
-	frameworkIRFile, err := os.CreateTemp(codegenTempDir, "terraform-framework-ir-*.json")
+	frameworkIRFile, err := os.CreateTemp(CodegenTempDir, "terraform-framework-ir-*.json")
 	defer func() {
 		frameworkIRFile.Close()
 	}()
 	if err != nil {
-		return specresource.Resource{}, fmt.Errorf("error creating temp file: %v", err)
+		return nil, fmt.Errorf("error creating temp file: %v", err)
 	}
 
 	// TODO it would be nice if there was a module interface for this
@@ -79,7 +97,7 @@ func GenerateResourceSpec(r ResourceConfig) (specresource.Resource, error) {
 		"generate",
 		"--config", yamlConfigFilename,
 		"--output", frameworkIRFilename,
-		r.OpenAPIConfig.Filename,
+		openAPIFilename,
 	}
 	slog.Debug(fmt.Sprintf("Executing %s", tfplugingenOpenAPIBinary), "args", args)
 	cmd := exec.Command(tfplugingenopenapiPath, args...)
@@ -89,17 +107,73 @@ func GenerateResourceSpec(r ResourceConfig) (specresource.Resource, error) {
 		if len(out) > 0 {
 			slog.Error("Command failed and produced output", "output", string(out))
 		}
-		return specresource.Resource{}, fmt.Errorf("error running tfplugingen-openapi: %v", err)
+		return nil, fmt.Errorf("error running tfplugingen-openapi: %v", err)
 	}
 
-	contents, err := os.ReadFile(frameworkIRFilename)
+	spec, err := readSpecification(frameworkIRFilename)
 	if err != nil {
-		return specresource.Resource{}, err
+		return nil, err
+	}
+	specs := map[string]specresource.Resource{}
+	for _, r := range spec.Resources {
+		specs[r.Name] = r
+	}
+	for _, r := range resources {
+		if _, ok := specs[r.Name]; !ok {
+			return nil, fmt.Errorf("no framework IR generated for resource %q", r.Name)
+		}
 	}
-	var spec spec.Specification
-	err = json.Unmarshal(contents, &spec)
+
+	if doc == nil {
+		return specs, nil
+	}
+	for _, r := range resources {
+		spec := specs[r.Name]
+		addOpenAPIValidators(doc, r, &spec)
+		specs[r.Name] = spec
+	}
+	return specs, nil
+}
+
+// readValidatorsOpenAPIDocument reads the OpenAPI spec that validators,
+// validation rules and validator tests are generated from, these are
+// skipped if it cannot be read
+func readValidatorsOpenAPIDocument(filename string) *OpenAPIDocument {
+	doc, err := readOpenAPIDocument(filename)
+	if err != nil {
+		slog.Warn("Could not read OpenAPI spec, skipping validators", "filename", filename, "err", err)
+		return nil
+	}
+	return doc
+}
+
+// GeneratePriorResourceSpec generates the spec.Resource for a prior schema
+// version, either from a framework IR snapshot or an older OpenAPI spec
+func GeneratePriorResourceSpec(r ResourceConfig, u StateUpgradeConfig) (specresource.Resource, error) {
+	if u.Snapshot != "" {
+		return readResourceSpec(u.Snapshot)
+	}
+	r.OpenAPIConfig = *u.OpenAPIConfig
+	return GenerateResourceSpec(r)
+}
+
+func readSpecification(filename string) (spec.Specification, error) {
+	var s spec.Specification
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(contents, &s)
+	return s, err
+}
+
+func readResourceSpec(filename string) (specresource.Resource, error) {
+	spec, err := readSpecification(filename)
 	if err != nil {
 		return specresource.Resource{}, err
 	}
+	if len(spec.Resources) == 0 {
+		return specresource.Resource{}, fmt.Errorf("no resources found in framework IR %q", filename)
+	}
 	return spec.Resources[0], nil
 }
@@ -5,72 +5,75 @@ package generator
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
-	"os"
 	"regexp"
-
-	openai "github.com/sashabaranov/go-openai"
 )
 
-const fileHeader = `package %s
+const fileHeader = `// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
 
-import (
-	"context"
-	"fmt"
-	"regexp"
+package %s
 
-	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
-)
+import (
+%s)
 `
 
 const systemPrompt = `You are a Terraform Provider Developer. You are writing code for the Kubernetes Provider using the newer terraform-plugin-framework. You will receive an attribute description and you will write a single Validator implementation that meets all the restrictions detailed in the description. The beginning of the description will provide the base type def for you to use. Do not perform any explanations, just write code. Do not define the whole file with imports or a main function, just write the interface implementation. If a package is to be imported do not assume an alias name use the package name directly, example: github.com/hashicorp/terraform-plugin-framework/schema/validator use "validator" but remember don't write the code imports. Be careful of the implied type, for a string validator the function to implement is ValidateString etc. Make sure you define the type struct that is passed to you. Make sure Description MarkdownDescription are implemented. There is no Validate function to implement, only Validate[Type].`
 
-var client *openai.Client
-
-func generateValidator(contents string, attrs AttributesGenerator) string {
-	if client == nil {
-		client = openai.NewClient(os.Getenv("OPENAI_API_KEY"))
-	}
-
-	for _, attr := range attrs {
+const examplesPrompt = `You are testing a validator for an attribute of a Terraform resource. You will receive the type of the attribute and its description. Reply with a JSON object with a "valid" array of values that meet all the restrictions detailed in the description and an "invalid" array of values that break one of them, with at most five values in each. Use JSON strings for a String, integers for an Int64, numbers for a Number and booleans for a Bool, and do not use null. If the description has no restrictions leave the "invalid" array empty. Do not perform any explanations, just write the JSON object.`
 
+// generateValidators returns the code for the validators of the attributes
+// and their nested attributes. The validator type is removed from attributes
+// that the generator returns no code for, so the schema does not use it.
+func generateValidators(ctx context.Context, g ValidatorGenerator, attrs AttributesGenerator) ([]string, error) {
+	validators := []string{}
+	for i := range attrs {
+		attr := &attrs[i]
 		if len(attr.NestedAttributes) != 0 {
-			contents = generateValidator(contents, attr.NestedAttributes)
+			nested, err := generateValidators(ctx, g, attr.NestedAttributes)
+			if err != nil {
+				return nil, err
+			}
+			validators = append(validators, nested...)
 		}
 
 		if attr.GenAIValidatorType == "" {
 			continue
 		}
 
-		slog.Info("Generating validator with OPENAI gpt4o", "validator", attr.GenAIValidatorType)
-		resp, err := client.CreateChatCompletion(
-			context.Background(),
-			openai.ChatCompletionRequest{
-				Model:       openai.GPT4o,
-				MaxTokens:   4095,
-				TopP:        0.3,
-				Temperature: 0.1,
-				Messages: []openai.ChatCompletionMessage{
-					{
-						Role:    openai.ChatMessageRoleSystem,
-						Content: systemPrompt,
-					},
-					{
-						Role:    openai.ChatMessageRoleUser,
-						Content: fmt.Sprintf("type %s struct{} %s", attr.GenAIValidatorType, attr.Description),
-					},
-				},
-			},
-		)
+		slog.Debug("Generating validator", "validator", attr.GenAIValidatorType)
+		v, err := g.GenerateValidator(ctx, ValidatorRequest{
+			Type:        attr.GenAIValidatorType,
+			Interface:   attr.ValidatorType,
+			Description: attr.Description,
+		})
 		if err != nil {
-			slog.Error(fmt.Sprintf("OPENAI error: %s", err), "validator", attr.GenAIValidatorType)
+			return nil, err
 		}
+		if v.Code == "" {
+			attr.GenAIValidatorType = ""
+			continue
+		}
+		attr.GenAIValidatorExamples = v.Examples
+		validators = append(validators, v.Code)
+	}
+	return validators, nil
+}
 
-		contents += "\n\n" + cleanResponse(resp.Choices[0].Message.Content) + "\n"
-
+// genAIValidatorRequests returns the requests for the validators the
+// attributes and their nested attributes use
+func genAIValidatorRequests(attrs AttributesGenerator) []ValidatorRequest {
+	reqs := []ValidatorRequest{}
+	for _, attr := range attrs {
+		reqs = append(reqs, genAIValidatorRequests(attr.NestedAttributes)...)
+		if attr.GenAIValidatorType != "" {
+			reqs = append(reqs, ValidatorRequest{
+				Type:        attr.GenAIValidatorType,
+				Interface:   attr.ValidatorType,
+				Description: attr.Description,
+			})
+		}
 	}
-	return contents
+	return reqs
 }
 
 func cleanResponse(response string) string {
@@ -12,22 +12,39 @@ type AttributeGenerator struct {
 	PlanModifierType    string
 	PlanModifierPackage string
 
+	// ValidatorType is the validator interface for the attribute, e.g. List for validator.List
+	ValidatorType string
+
 	Required           bool
 	Description        string
 	Computed           bool
 	Sensitive          bool
 	Immutable          bool
+	DeprecationMessage string
 	GenAIValidatorType string
 
+	// GenAIValidatorExamples are the values the genAI validator is tested with
+	GenAIValidatorExamples ValidatorExamples
+
+	// Default is the Go expression for the default value of the attribute
+	Default string
+
+	// Validators and PlanModifiers are Go expressions set in the attribute configuration
+	Validators    []string
+	PlanModifiers []string
+
+	// Imports are the packages needed by Default, Validators and PlanModifiers
+	Imports []string
+
 	NestedAttributes AttributesGenerator
 }
 
-func (g AttributeGenerator) String() string {
+func (g AttributeGenerator) Render() (string, error) {
 	return renderTemplate(attributeTemplate, g)
 }
 
 type AttributesGenerator []AttributeGenerator
 
-func (g AttributesGenerator) String() string {
+func (g AttributesGenerator) Render() (string, error) {
 	return renderTemplate(attributesTemplate, g)
 }
@@ -19,12 +19,12 @@ type ModelFieldGenerator struct {
 	NestedFields ModelFieldsGenerator
 }
 
-func (g ModelFieldGenerator) String() string {
+func (g ModelFieldGenerator) Render() (string, error) {
 	return renderTemplate(modelFieldTemplate, g)
 }
 
 type ModelFieldsGenerator []ModelFieldGenerator
 
-func (g ModelFieldsGenerator) String() string {
+func (g ModelFieldsGenerator) Render() (string, error) {
 	return renderTemplate(modelFieldsTemplate, g)
 }
@@ -4,9 +4,10 @@
 package generator
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"path"
 	"strings"
 	"time"
 
@@ -15,13 +16,21 @@ import (
 )
 
 type ResourceGenerator struct {
-	GeneratedTimestamp time.Time
+	GeneratedTimestamp *time.Time
+	Provider           ProviderConfig
 	ResourceConfig     ResourceConfig
 	Schema             SchemaGenerator
 	ModelFields        ModelFieldsGenerator
+	StateUpgrades      []StateUpgradeGenerator
+	ValidationRules    []ValidationRuleGenerator
+	ValidatorGenerator ValidatorGenerator
+	GenAIValidators    []string
+
+	ValidatorTests       []ValidatorTestsGenerator
+	ValidatorTestImports []string
 }
 
-func NewResourceGenerator(cfg ResourceConfig, spec specresource.Resource) ResourceGenerator {
+func NewResourceGenerator(provider ProviderConfig, cfg ResourceConfig, spec specresource.Resource) ResourceGenerator {
 	attributes := AttributesGenerator{{
 		Name:          "id",
 		AttributeType: StringAttributeType,
@@ -50,71 +59,123 @@ func NewResourceGenerator(cfg ResourceConfig, spec specresource.Resource) Resour
 	}
 
 	return ResourceGenerator{
-		GeneratedTimestamp: time.Now(),
+		GeneratedTimestamp: generatedTimestamp(),
+		Provider:           provider,
 		ResourceConfig:     cfg,
-		ModelFields:        append(modelFields, GenerateModelFields(spec.Schema.Attributes, cfg.IgnoredAttributes, "")...),
+		ModelFields:        append(modelFields, GenerateModelFields(spec.Schema.Attributes, cfg, "")...),
 		Schema: SchemaGenerator{
 			Name:        cfg.Name,
 			Description: cfg.Description,
-			Attributes:  append(attributes, GenerateAttributes(spec.Schema.Attributes, cfg.Name, cfg.Generate.GenAIValidation, cfg.IgnoredAttributes, cfg.ComputedAttributes, cfg.RequiredAttributes, cfg.SensitiveAttributes, cfg.ImmutableAttributes, "")...),
+			Version:     cfg.SchemaVersion,
+			Attributes:  append(attributes, GenerateAttributes(spec.Schema.Attributes, cfg, cfg.Name, "")...),
 		},
 	}
 }
 
-func (g *ResourceGenerator) GenerateSchemaFunctionCode() string {
-	imports := getPlanModifierImports(g.Schema.Attributes)
-
-	if len(imports) > 0 {
-		imports = append(imports, path.Join(schemaImportPath, "planmodifier"))
-		g.Schema.Imports = imports
-	}
-
+func (g *ResourceGenerator) GenerateSchemaFunctionCode() (string, error) {
+	g.Schema.Imports = getSchemaImports(g.Schema.Attributes)
 	return renderTemplate(schemaFunctionTemplate, g)
 }
 
-func (g *ResourceGenerator) GenerateCRUDStubCode() string {
+func (g *ResourceGenerator) GenerateCRUDStubCode() (string, error) {
 	return renderTemplate(crudStubsTemplate, g)
 }
 
-func (g *ResourceGenerator) GenerateResourceCode() string {
+func (g *ResourceGenerator) GenerateResourceCode() (string, error) {
 	return renderTemplate(resourceTemplate, g)
 }
 
-func (g *ResourceGenerator) GenerateModelCode() string {
+func (g *ResourceGenerator) GenerateModelCode() (string, error) {
 	return renderTemplate(modelTemplate, g)
 }
 
-func (g *ResourceGenerator) GenerateAutoCRUDCode() string {
+func (g *ResourceGenerator) GenerateAutoCRUDCode() (string, error) {
 	return renderTemplate(autocrudTemplate, g)
 }
 
-func (g *ResourceGenerator) GenerateAutoCRUDHooksCode() string {
+func (g *ResourceGenerator) GenerateAutoCRUDHooksCode() (string, error) {
 	return renderTemplate(autocrudHooksTemplate, g)
 }
 
-func (g *ResourceGenerator) GenerateGenAIValidatorsCode() string {
-	return generateValidator(fmt.Sprintf(fileHeader, g.ResourceConfig.Package), g.Schema.Attributes)
+func (g *ResourceGenerator) GenerateStateUpgradeCode() (string, error) {
+	return renderTemplate(stateUpgradeTemplate, g)
+}
+
+func (g *ResourceGenerator) GenerateMoveStateCode() (string, error) {
+	return renderTemplate(moveStateTemplate, g)
+}
+
+func (g *ResourceGenerator) GenerateCELValidationCode() (string, error) {
+	return renderTemplate(celValidationTemplate, g)
+}
+
+// GenerateGenAIValidators generates the validators for the attributes using
+// the ValidatorGenerator, it must be called before the schema is rendered as
+// attributes that have no validator are updated
+func (g *ResourceGenerator) GenerateGenAIValidators(ctx context.Context) error {
+	if g.ValidatorGenerator == nil {
+		return fmt.Errorf("no validator generator configured")
+	}
+	validators, err := generateValidators(ctx, g.ValidatorGenerator, g.Schema.Attributes)
+	if err != nil {
+		return err
+	}
+	g.GenAIValidators = validators
+	return nil
+}
+
+// CheckGenAIValidators type checks the generated validators together, as
+// they are written to the same file where they can conflict, e.g. when two
+// of them declare the same helper function
+func (g *ResourceGenerator) CheckGenAIValidators() error {
+	err := checkValidators(g.ResourceConfig.Package, genAIValidatorRequests(g.Schema.Attributes), g.GenAIValidators)
+	if errors.Is(err, errValidatorCheckUnavailable) {
+		slog.Warn("Skipping check of generated validators", "resource", g.ResourceConfig.Name, "err", err)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("generated validators do not compile together: %v", err)
+	}
+	return nil
+}
+
+func (g *ResourceGenerator) GenerateValidatorTestsCode() (string, error) {
+	return renderTemplate(validatorTestsTemplate, g)
+}
+
+func (g *ResourceGenerator) GenerateGenAIValidatorsCode() (string, error) {
+	return g.Provider.LicenseComment() + validatorsSource(g.ResourceConfig.Package, g.GenAIValidators), nil
 }
 
 // TODO create a walkAttributes function that abstracts the logic of traversing
 // the spec for attributes
 
-// FIXME this function has too many parameters now, should maybe be part of ResourceGenerator.
-func GenerateAttributes(attrs specresource.Attributes, resourceName string, genAIValidation bool, ignored, computed, required, sensitive, immutable []string, path string) AttributesGenerator {
+func GenerateAttributes(attrs specresource.Attributes, cfg ResourceConfig, resourceName string, path string) AttributesGenerator {
+	genAIValidation := cfg.Generate.GenAIValidation
 	generatedAttrs := AttributesGenerator{}
 	for _, attr := range attrs {
 		attributePath := path + attr.Name
+		attrConfig := cfg.attributeConfig(attributePath)
 
-		if stringInSlice(attributePath, ignored) {
+		if attrConfig.IsIgnored() {
 			continue
 		}
 
+		if attrConfig.Default == nil {
+			if value, ok := specDefault(attr, cfg.isBuiltinType()); ok {
+				attrConfig.Default = &value
+			}
+		}
+
 		generatedAttr := AttributeGenerator{
-			Name:      attr.Name,
-			Required:  stringInSlice(attributePath, required),
-			Computed:  stringInSlice(attributePath, computed),
-			Sensitive: stringInSlice(attributePath, sensitive),
-			Immutable: stringInSlice(attributePath, immutable),
+			Name:               attrConfig.Name(attr.Name),
+			Required:           attrConfig.IsRequired(),
+			Computed:           attrConfig.IsComputed(),
+			Sensitive:          attrConfig.IsSensitive(),
+			Immutable:          attrConfig.IsImmutable(),
+			DeprecationMessage: attrConfig.DeprecationMessage,
+			Validators:         attrConfig.Validators,
+			PlanModifiers:      attrConfig.PlanModifiers,
+			Imports:            attrConfig.imports(),
 		}
 
 		switch {
@@ -125,9 +186,7 @@ func GenerateAttributes(attrs specresource.Attributes, resourceName string, genA
 			generatedAttr.AttributeType = BoolAttributeType
 			generatedAttr.PlanModifierType = BoolPlanModifierType
 			generatedAttr.PlanModifierPackage = BoolPlanModifierPackage
-			if genAIValidation {
-				generatedAttr.GenAIValidatorType = resourceName + "_" + attr.Name + "_validator"
-			}
+			generatedAttr.ValidatorType = BoolValidatorType
 		case attr.String != nil:
 			if attr.String.Description != nil {
 				generatedAttr.Description = sanitizeDescription(*attr.String.Description)
@@ -135,9 +194,7 @@ func GenerateAttributes(attrs specresource.Attributes, resourceName string, genA
 			generatedAttr.AttributeType = StringAttributeType
 			generatedAttr.PlanModifierType = StringPlanModifierType
 			generatedAttr.PlanModifierPackage = StringPlanModifierPackage
-			if genAIValidation {
-				generatedAttr.GenAIValidatorType = resourceName + "_" + attr.Name + "_validator"
-			}
+			generatedAttr.ValidatorType = StringValidatorType
 		case attr.Number != nil:
 			if attr.Number.Description != nil {
 				generatedAttr.Description = sanitizeDescription(*attr.Number.Description)
@@ -145,9 +202,7 @@ func GenerateAttributes(attrs specresource.Attributes, resourceName string, genA
 			generatedAttr.AttributeType = NumberAttributeType
 			generatedAttr.PlanModifierType = NumberPlanModifierType
 			generatedAttr.PlanModifierPackage = NumberPlanModifierPackage
-			if genAIValidation {
-				generatedAttr.GenAIValidatorType = resourceName + "_" + attr.Name + "_validator"
-			}
+			generatedAttr.ValidatorType = NumberValidatorType
 		case attr.Int64 != nil:
 			if attr.Int64.Description != nil {
 				generatedAttr.Description = sanitizeDescription(*attr.Int64.Description)
@@ -155,21 +210,34 @@ func GenerateAttributes(attrs specresource.Attributes, resourceName string, genA
 			generatedAttr.AttributeType = Int64AttributeType
 			generatedAttr.PlanModifierType = Int64PlanModifierType
 			generatedAttr.PlanModifierPackage = Int64PlanModifierPackage
-			if genAIValidation {
-				generatedAttr.GenAIValidatorType = resourceName + "_" + attr.Name + "_validator"
-			}
+			generatedAttr.ValidatorType = Int64ValidatorType
 		case attr.Map != nil:
 			if attr.Map.Description != nil {
 				generatedAttr.Description = sanitizeDescription(*attr.Map.Description)
 			}
 			generatedAttr.AttributeType = MapAttributeType
 			generatedAttr.ElementType = getElementType(attr.Map.ElementType)
+			generatedAttr.PlanModifierType = MapPlanModifierType
+			generatedAttr.PlanModifierPackage = MapPlanModifierPackage
+			generatedAttr.ValidatorType = MapValidatorType
 		case attr.List != nil:
 			if attr.List.Description != nil {
 				generatedAttr.Description = sanitizeDescription(*attr.List.Description)
 			}
 			generatedAttr.AttributeType = ListAttributeType
 			generatedAttr.ElementType = getElementType(attr.List.ElementType)
+			generatedAttr.PlanModifierType = ListPlanModifierType
+			generatedAttr.PlanModifierPackage = ListPlanModifierPackage
+			generatedAttr.ValidatorType = ListValidatorType
+		case attr.Set != nil:
+			if attr.Set.Description != nil {
+				generatedAttr.Description = sanitizeDescription(*attr.Set.Description)
+			}
+			generatedAttr.AttributeType = SetAttributeType
+			generatedAttr.ElementType = getElementType(attr.Set.ElementType)
+			generatedAttr.PlanModifierType = SetPlanModifierType
+			generatedAttr.PlanModifierPackage = SetPlanModifierPackage
+			generatedAttr.ValidatorType = SetValidatorType
 		case attr.SingleNested != nil:
 			if attr.SingleNested.Description != nil {
 				generatedAttr.Description = sanitizeDescription(*attr.SingleNested.Description)
@@ -177,41 +245,73 @@ func GenerateAttributes(attrs specresource.Attributes, resourceName string, genA
 			generatedAttr.AttributeType = SingleNestedAttributeType
 			generatedAttr.PlanModifierType = ObjectPlanModifierType
 			generatedAttr.PlanModifierPackage = ObjectPlanModifierPackage
-			generatedAttr.NestedAttributes = GenerateAttributes(attr.SingleNested.Attributes, resourceName+"_"+attr.Name, genAIValidation, ignored, computed, required, sensitive, immutable, attributePath+".")
+			generatedAttr.ValidatorType = ObjectValidatorType
+			generatedAttr.NestedAttributes = GenerateAttributes(attr.SingleNested.Attributes, cfg, resourceName+"_"+attr.Name, attributePath+".")
 		case attr.ListNested != nil:
 			if attr.ListNested.Description != nil {
 				generatedAttr.Description = sanitizeDescription(*attr.ListNested.Description)
 			}
 			generatedAttr.AttributeType = ListNestedAttributeType
-			generatedAttr.NestedAttributes = GenerateAttributes(attr.ListNested.NestedObject.Attributes, resourceName+"_"+attr.Name, genAIValidation, ignored, computed, required, sensitive, immutable, attributePath+"[*].")
+			generatedAttr.PlanModifierType = ListPlanModifierType
+			generatedAttr.PlanModifierPackage = ListPlanModifierPackage
+			generatedAttr.ValidatorType = ListValidatorType
+			generatedAttr.NestedAttributes = GenerateAttributes(attr.ListNested.NestedObject.Attributes, cfg, resourceName+"_"+attr.Name, attributePath+"[*].")
+		case attr.SetNested != nil:
+			if attr.SetNested.Description != nil {
+				generatedAttr.Description = sanitizeDescription(*attr.SetNested.Description)
+			}
+			generatedAttr.AttributeType = SetNestedAttributeType
+			generatedAttr.PlanModifierType = SetPlanModifierType
+			generatedAttr.PlanModifierPackage = SetPlanModifierPackage
+			generatedAttr.ValidatorType = SetValidatorType
+			generatedAttr.NestedAttributes = GenerateAttributes(attr.SetNested.NestedObject.Attributes, cfg, resourceName+"_"+attr.Name, attributePath+"[*].")
+		}
+		if genAIValidation && generatedAttr.ValidatorType != "" {
+			generatedAttr.GenAIValidatorType = resourceName + "_" + attr.Name + "_validator"
+		}
+		specValidatorDefinitions := []string{}
+		for _, v := range specValidators(attr) {
+			if v == nil {
+				continue
+			}
+			specValidatorDefinitions = append(specValidatorDefinitions, v.SchemaDefinition)
+			for _, i := range v.Imports {
+				generatedAttr.Imports = append(generatedAttr.Imports, i.Path)
+			}
+		}
+		generatedAttr.Validators = append(specValidatorDefinitions, generatedAttr.Validators...)
+		if attrConfig.Description != nil {
+			generatedAttr.Description = sanitizeDescription(*attrConfig.Description)
+		}
+		if attrConfig.Default != nil {
+			// the default is checked by ValidateAttributes
+			if expr, imports, err := defaultValue(attr, *attrConfig.Default); err == nil {
+				generatedAttr.Default = expr
+				generatedAttr.Imports = append(generatedAttr.Imports, imports...)
+			} else {
+				slog.Warn("Ignoring default for attribute", "path", attributePath, "err", err)
+			}
 		}
 		generatedAttrs = append(generatedAttrs, generatedAttr)
 	}
 	return generatedAttrs
 }
 
-func stringInSlice(str string, slice []string) bool {
-	for _, v := range slice {
-		if v == str {
-			return true
-		}
-	}
-	return false
-}
-
-func GenerateModelFields(attrs specresource.Attributes, ignored []string, path string) ModelFieldsGenerator {
+func GenerateModelFields(attrs specresource.Attributes, cfg ResourceConfig, path string) ModelFieldsGenerator {
 	generatedModelFields := ModelFieldsGenerator{}
 	for _, attr := range attrs {
 		attributePath := path + attr.Name
+		attrConfig := cfg.attributeConfig(attributePath)
 
-		if stringInSlice(attributePath, ignored) {
+		if attrConfig.IsIgnored() {
 			continue
 		}
 
+		name := attrConfig.Name(attr.Name)
 		generatedModelField := ModelFieldGenerator{
-			FieldName:         MapTerraformAttributeToModel(attr.Name),
+			FieldName:         MapTerraformAttributeToModel(name),
 			ManifestFieldName: MapTerraformAttributeToKubernetes(attr.Name),
-			AttributeName:     attr.Name,
+			AttributeName:     name,
 		}
 		switch {
 		case attr.Bool != nil:
@@ -232,16 +332,26 @@ func GenerateModelFields(attrs specresource.Attributes, ignored []string, path s
 		case attr.List != nil:
 			generatedModelField.AttributeType = ListAttributeType
 			generatedModelField.ElementType = getModelElementType(attr.List.ElementType)
+		case attr.Set != nil:
+			generatedModelField.AttributeType = SetAttributeType
+			generatedModelField.ElementType = getModelElementType(attr.Set.ElementType)
 		case attr.SingleNested != nil:
 			generatedModelField.AttributeType = SingleNestedAttributeType
-			generatedModelField.NestedFields = GenerateModelFields(attr.SingleNested.Attributes, ignored, attributePath+".")
+			generatedModelField.NestedFields = GenerateModelFields(attr.SingleNested.Attributes, cfg, attributePath+".")
 			if len(generatedModelField.NestedFields) == 0 {
 				slog.Warn("Ignoring nested attribute with no schema", "name", attr.Name)
 				continue
 			}
 		case attr.ListNested != nil:
 			generatedModelField.AttributeType = ListNestedAttributeType
-			generatedModelField.NestedFields = GenerateModelFields(attr.ListNested.NestedObject.Attributes, ignored, attributePath+"[*].")
+			generatedModelField.NestedFields = GenerateModelFields(attr.ListNested.NestedObject.Attributes, cfg, attributePath+"[*].")
+			if len(generatedModelField.NestedFields) == 0 {
+				slog.Warn("Ignoring nested attribute with no schema", "name", attr.Name)
+				continue
+			}
+		case attr.SetNested != nil:
+			generatedModelField.AttributeType = SetNestedAttributeType
+			generatedModelField.NestedFields = GenerateModelFields(attr.SetNested.NestedObject.Attributes, cfg, attributePath+"[*].")
 			if len(generatedModelField.NestedFields) == 0 {
 				slog.Warn("Ignoring nested attribute with no schema", "name", attr.Name)
 				continue
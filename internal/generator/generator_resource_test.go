@@ -0,0 +1,219 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+	specschema "github.com/hashicorp/terraform-plugin-codegen-spec/schema"
+)
+
+func testResourceConfig(t *testing.T) ResourceConfig {
+	t.Helper()
+	cfg, err := validateTimeoutDurations(ResourceConfig{
+		Name:                 "kubernetes_widget_v1",
+		Package:              "widgetv1",
+		OutputFilenamePrefix: "widget",
+		APIVersion:           "example.com/v1",
+		Kind:                 "Widget",
+		Description:          "Widgets are an example resource",
+		Attributes: []AttributeConfig{
+			{Path: "metadata.uid", Ignored: ptr(true)},
+			{Path: "metadata.name", Immutable: ptr(true)},
+			{Path: "metadata.namespace", Immutable: ptr(true)},
+			{Path: "spec.replicas", Immutable: ptr(true)},
+			{Path: "spec.ports[*].name", Immutable: ptr(true)},
+		},
+		Generate: GenerateConfig{
+			Schema:   true,
+			Model:    true,
+			CRUDAuto: true,
+			CRUDAutoOptions: &CRUDAutoOptions{
+				Hooks: &Hooks{
+					BeforeHook: &BeforeHook{Create: true},
+					AfterHook:  &AfterHook{Read: true},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func generateAllCode(t *testing.T, cfg ResourceConfig) map[string]string {
+	t.Helper()
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := NewResourceGenerator(DefaultProviderConfig(), cfg, spec)
+	generators := map[string]func() (string, error){
+		"resource": gen.GenerateResourceCode,
+		"schema":   gen.GenerateSchemaFunctionCode,
+		"model":    gen.GenerateModelCode,
+		"autocrud": gen.GenerateAutoCRUDCode,
+		"hooks":    gen.GenerateAutoCRUDHooksCode,
+		"stubs":    gen.GenerateCRUDStubCode,
+		"list":     NewResourcesListGenerator([]ResourceConfig{cfg}, DefaultProviderConfig()).Render,
+	}
+	code := map[string]string{}
+	for name, generate := range generators {
+		c, err := generate()
+		if err != nil {
+			t.Fatalf("error generating %s code: %v", name, err)
+		}
+		code[name] = c
+	}
+	return code
+}
+
+func TestGenerateReproducible(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	cfg := testResourceConfig(t)
+
+	first := generateAllCode(t, cfg)
+	second := generateAllCode(t, cfg)
+	for name, code := range first {
+		if code != second[name] {
+			t.Errorf("%s code differs between runs:\n%s\n\n%s", name, code, second[name])
+		}
+		if strings.Contains(code, "written by a robot") {
+			t.Errorf("%s code contains a timestamp without SOURCE_DATE_EPOCH set", name)
+		}
+	}
+}
+
+func TestGenerateSourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	code, err := NewResourcesListGenerator([]ResourceConfig{testResourceConfig(t)}, DefaultProviderConfig()).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "This code was written by a robot on Nov 14, 2023 22:13:20 UTC."
+	if !strings.Contains(code, expected) {
+		t.Fatalf("expected code to contain %q:\n%s", expected, code)
+	}
+}
+
+func TestResourcesListSorted(t *testing.T) {
+	resources := []ResourceConfig{
+		{Name: "kubernetes_service_v1", Package: "corev1", Kind: "Service"},
+		{Name: "kubernetes_deployment_v1", Package: "appsv1", Kind: "Deployment"},
+		{Name: "kubernetes_config_map_v1", Package: "corev1", Kind: "ConfigMap"},
+	}
+	reversed := []ResourceConfig{resources[2], resources[1], resources[0]}
+
+	first := NewResourcesListGenerator(resources, DefaultProviderConfig())
+	second := NewResourcesListGenerator(reversed, DefaultProviderConfig())
+	firstCode, err := first.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondCode, err := second.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstCode != secondCode {
+		t.Fatalf("resources list depends on input order:\n%s\n\n%s", firstCode, secondCode)
+	}
+	if strings.Join(first.Packages, ",") != "appsv1,corev1" {
+		t.Fatalf("expected sorted packages got %v", first.Packages)
+	}
+}
+
+func TestGeneratedHeader(t *testing.T) {
+	for name, code := range generateAllCode(t, testResourceConfig(t)) {
+		if name == "hooks" || name == "stubs" {
+			if IsGeneratedSource([]byte(code)) {
+				t.Errorf("expected %s code not to have the generated code header", name)
+			}
+			if !IsScaffoldedSource([]byte(code)) {
+				t.Errorf("expected %s code to have the scaffolded code header", name)
+			}
+			continue
+		}
+		if !IsGeneratedSource([]byte(code)) {
+			t.Errorf("expected %s code to have the generated code header", name)
+		}
+	}
+}
+
+func TestLicenseHeader(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := DefaultProviderConfig()
+	provider.LicenseHeader = "Copyright (c) Example Corp.\n\nSPDX-License-Identifier: MPL-2.0\n"
+	gen := NewResourceGenerator(provider, testResourceConfig(t), spec)
+	code, err := gen.GenerateResourceCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "// Copyright (c) Example Corp.\n//\n// SPDX-License-Identifier: MPL-2.0\n\n// Code generated by"
+	if !strings.HasPrefix(code, expected) {
+		t.Fatalf("expected %q got %q", expected, code[:len(expected)])
+	}
+}
+
+func TestGenerateSetAttributes(t *testing.T) {
+	cfg := testResourceConfig(t)
+	cfg.Attributes = []AttributeConfig{{Path: "spec.hosts", Immutable: ptr(true)}}
+	cfg.Generate.GenAIValidation = true
+	spec := specresource.Resource{
+		Name: cfg.Name,
+		Schema: &specresource.Schema{Attributes: specresource.Attributes{
+			{Name: "spec", SingleNested: &specresource.SingleNestedAttribute{Attributes: specresource.Attributes{
+				{Name: "finalizers", Set: &specresource.SetAttribute{ElementType: specschema.ElementType{String: &specschema.StringType{}}}},
+				{Name: "hosts", SetNested: &specresource.SetNestedAttribute{NestedObject: specresource.NestedAttributeObject{
+					Attributes: specresource.Attributes{{Name: "host_name", String: &specresource.StringAttribute{}}},
+				}}},
+			}}},
+		}},
+	}
+	gen := NewResourceGenerator(DefaultProviderConfig(), cfg, spec)
+
+	for name, generate := range map[string]func() (string, error){
+		"schema": gen.GenerateSchemaFunctionCode,
+		"model":  gen.GenerateModelCode,
+	} {
+		code, err := generate()
+		if err != nil {
+			t.Fatalf("error generating %s code: %v", name, err)
+		}
+		formatted, err := format.Source([]byte(code))
+		if err != nil {
+			t.Fatalf("generated %s code is not valid Go: %v", name, err)
+		}
+		code = string(formatted)
+		expected := map[string][]string{
+			"schema": {
+				`"finalizers": schema.SetAttribute{`,
+				"types.StringType,",
+				"Validators: []validator.Set{\n\t\t\t\t\t\t\tkubernetes_widget_v1_spec_finalizers_validator{},",
+				`"hosts": schema.SetNestedAttribute{`,
+				"setplanmodifier.RequiresReplace(),",
+				"Validators: []validator.Set{\n\t\t\t\t\t\t\tkubernetes_widget_v1_spec_hosts_validator{},",
+				"NestedObject: schema.NestedAttributeObject{",
+				`"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"`,
+			},
+			"model": {
+				"Finalizers []types.String `tfsdk:\"finalizers\" manifest:\"finalizers\"`",
+				"Hosts      []struct {",
+				"HostName types.String `tfsdk:\"host_name\" manifest:\"hostName\"`",
+			},
+		}[name]
+		for _, e := range expected {
+			if !strings.Contains(code, e) {
+				t.Errorf("expected %s code to contain %q got:\n%s", name, e, code)
+			}
+		}
+	}
+}
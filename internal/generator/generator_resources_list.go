@@ -3,14 +3,46 @@
 
 package generator
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 type ResourcesListGenerator struct {
-	GeneratedTimestamp time.Time
+	GeneratedTimestamp *time.Time
 	Resources          []ResourceConfig
 	Packages           []string
+	Provider           ProviderConfig
 }
 
-func (p ResourcesListGenerator) String() string {
+// NewResourcesListGenerator creates a ResourcesListGenerator with the
+// resources and their packages sorted so the output is reproducible
+func NewResourcesListGenerator(resources []ResourceConfig, provider ProviderConfig) ResourcesListGenerator {
+	sorted := make([]ResourceConfig, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	packages := []string{}
+	packageMap := map[string]struct{}{}
+	for _, r := range sorted {
+		if _, ok := packageMap[r.Package]; ok {
+			continue
+		}
+		packageMap[r.Package] = struct{}{}
+		packages = append(packages, r.Package)
+	}
+	sort.Strings(packages)
+
+	return ResourcesListGenerator{
+		GeneratedTimestamp: generatedTimestamp(),
+		Resources:          sorted,
+		Packages:           packages,
+		Provider:           provider,
+	}
+}
+
+func (p ResourcesListGenerator) Render() (string, error) {
 	return renderTemplate(resourcesListTemplate, p)
 }
@@ -5,31 +5,68 @@ package generator
 
 import (
 	"path"
+	"sort"
 )
 
 type SchemaGenerator struct {
 	Name        string
 	Description string
+	Version     int64
 	Attributes  AttributesGenerator
 	Imports     []string
 }
 
-func (g SchemaGenerator) String() string {
+func (g SchemaGenerator) Render() (string, error) {
 	return renderTemplate(schemaTemplate, g)
 }
 
-const schemaImportPath = "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+// UsesElementTypes returns true if any attribute in the schema needs
+// the types package for its element type
+func (g SchemaGenerator) UsesElementTypes() bool {
+	return usesElementTypes(g.Attributes)
+}
+
+func usesElementTypes(a AttributesGenerator) bool {
+	for _, aa := range a {
+		if aa.ElementType != "" || usesElementTypes(aa.NestedAttributes) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	schemaImportPath    = "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	validatorImportPath = "github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
 
 // FIXME this should probably be a reciever function
-func getPlanModifierImports(a AttributesGenerator) []string {
+func getSchemaImports(a AttributesGenerator) []string {
+	importMap := map[string]struct{}{}
+	collectSchemaImports(importMap, a)
+
 	imports := []string{}
+	for k := range importMap {
+		imports = append(imports, k)
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+func collectSchemaImports(imports map[string]struct{}, a AttributesGenerator) {
 	for _, aa := range a {
 		if aa.Immutable {
-			imports = append(imports, path.Join(schemaImportPath, aa.PlanModifierPackage))
+			imports[path.Join(schemaImportPath, aa.PlanModifierPackage)] = struct{}{}
+		}
+		if aa.Immutable || len(aa.PlanModifiers) > 0 {
+			imports[path.Join(schemaImportPath, "planmodifier")] = struct{}{}
 		}
-		imports = append(imports, getPlanModifierImports(aa.NestedAttributes)...)
+		if aa.GenAIValidatorType != "" || len(aa.Validators) > 0 {
+			imports[validatorImportPath] = struct{}{}
+		}
+		for _, i := range aa.Imports {
+			imports[i] = struct{}{}
+		}
+		collectSchemaImports(imports, aa.NestedAttributes)
 	}
-
-	// FIXME: this will need to dedupe
-	return imports
 }
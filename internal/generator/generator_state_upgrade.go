@@ -0,0 +1,80 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+)
+
+// StateUpgradeGenerator generates a state upgrader from a prior schema version
+type StateUpgradeGenerator struct {
+	Version     int64
+	PriorSchema SchemaGenerator
+	Moves       []AttributeMoveConfig
+}
+
+// NewStateUpgradeGenerator creates a StateUpgradeGenerator using the spec
+// generated for the prior schema version
+func NewStateUpgradeGenerator(cfg ResourceConfig, u StateUpgradeConfig, spec specresource.Resource) StateUpgradeGenerator {
+	// the prior schema is only used to decode state, so it doesn't need
+	// plan modifiers, defaults or validators
+	cfg.Generate.GenAIValidation = false
+	cfg.SchemaVersion = u.Version
+	cfg.Attributes = priorAttributeConfigs(cfg, u.Moves)
+
+	prior := NewResourceGenerator(ProviderConfig{}, cfg, spec)
+	prior.Schema.Attributes = priorSchemaAttributes(prior.Schema.Attributes)
+	return StateUpgradeGenerator{
+		Version:     u.Version,
+		PriorSchema: prior.Schema,
+		Moves:       u.Moves,
+	}
+}
+
+// priorAttributeConfigs removes the renames that are the destination of a
+// move, as the prior schema has the attribute under its original name. Other
+// renames are assumed to have been made before the prior schema version.
+func priorAttributeConfigs(cfg ResourceConfig, moves []AttributeMoveConfig) []AttributeConfig {
+	moved := map[string]bool{}
+	for _, m := range moves {
+		moved[m.To] = true
+	}
+	attrs := []AttributeConfig{}
+	for _, a := range cfg.Attributes {
+		if a.Rename != "" {
+			if attributePath, _, ok := cfg.schemaPath(a.Path); ok && moved[attributePath] {
+				a.Rename = ""
+			}
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+// priorSchemaAttributes removes the options that are only used when planning
+func priorSchemaAttributes(attrs AttributesGenerator) AttributesGenerator {
+	prior := AttributesGenerator{}
+	for _, a := range attrs {
+		a.Immutable = false
+		a.DeprecationMessage = ""
+		a.Default = ""
+		a.Validators = nil
+		a.PlanModifiers = nil
+		a.Imports = nil
+		a.NestedAttributes = priorSchemaAttributes(a.NestedAttributes)
+		prior = append(prior, a)
+	}
+	return prior
+}
+
+// StateUpgradesUseElementTypes returns true if any prior schema needs
+// the types package for its element types
+func (g *ResourceGenerator) StateUpgradesUseElementTypes() bool {
+	for _, u := range g.StateUpgrades {
+		if u.PriorSchema.UsesElementTypes() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStateUpgradePriorSchemaRenames(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testResourceConfig(t)
+	cfg.Attributes = append(cfg.Attributes,
+		AttributeConfig{Path: "spec.replicas", Rename: "replica_count"},
+		AttributeConfig{Path: "spec.ports[*].name", Rename: "port_name"},
+	)
+
+	u := StateUpgradeConfig{
+		Version: 0,
+		Moves:   []AttributeMoveConfig{{From: "spec.replicas", To: "spec.replica_count"}},
+	}
+	prior := NewStateUpgradeGenerator(cfg, u, spec)
+
+	names := map[string]bool{}
+	var collect func(AttributesGenerator, string)
+	collect = func(attrs AttributesGenerator, prefix string) {
+		for _, a := range attrs {
+			names[prefix+a.Name] = true
+			collect(a.NestedAttributes, prefix+a.Name+".")
+		}
+	}
+	collect(prior.PriorSchema.Attributes, "")
+
+	if !names["spec.replicas"] || names["spec.replica_count"] {
+		t.Errorf("expected the prior schema to use the name from before the move got %v", names)
+	}
+	if !names["spec.ports.port_name"] {
+		t.Errorf("expected renames without a move to be kept in the prior schema got %v", names)
+	}
+}
+
+func TestStateUpgradeMovesQuoted(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testResourceConfig(t)
+	u := StateUpgradeConfig{
+		Version: 0,
+		Moves:   []AttributeMoveConfig{{From: `spec."replicas"`, To: `spec\replicas`}},
+	}
+	gen := NewResourceGenerator(DefaultProviderConfig(), cfg, spec)
+	gen.StateUpgrades = []StateUpgradeGenerator{NewStateUpgradeGenerator(cfg, u, spec)}
+	code, err := gen.GenerateStateUpgradeCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{From: "spec.\"replicas\"", To: "spec\\replicas", Key: "", Unwrap: false}`
+	if !strings.Contains(code, expected) {
+		t.Errorf("expected the move paths to be quoted as %s in:\n%s", expected, code)
+	}
+}
+
+func TestValidateStateUpgrades(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		moves []AttributeMoveConfig
+		err   string
+	}{
+		{"move", []AttributeMoveConfig{{From: "spec.replicas", To: "spec.replica_count"}}, ""},
+		{"unwrap", []AttributeMoveConfig{{From: "spec", To: "spec", Unwrap: true}}, ""},
+		{"key", []AttributeMoveConfig{{From: "spec.ports", To: "spec.ports", Key: "name"}}, ""},
+		{"empty from", []AttributeMoveConfig{{To: "spec.replicas"}}, "must set both paths"},
+		{"empty to", []AttributeMoveConfig{{From: "spec.replicas"}}, "must set both paths"},
+		{"same path", []AttributeMoveConfig{{From: "spec.replicas", To: "spec.replicas"}}, "must set unwrap or key"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := ResourceConfig{Name: "kubernetes_widget_v1", SchemaVersion: 1, StateUpgrades: []StateUpgradeConfig{
+				{Version: 0, Snapshot: "prior.json", Moves: tc.moves},
+			}}
+			err := validateStateUpgrades(r)
+			if tc.err == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+				t.Errorf("expected an error containing %q got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestMoveStateQuoted(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testResourceConfig(t)
+	cfg.MoveFrom = []MoveFromConfig{{
+		ResourceType: `kubernetes_"widget"`,
+		Moves:        []AttributeMoveConfig{{From: `spec\ports`, To: "spec.ports", Key: `"name"`}},
+	}}
+	gen := NewResourceGenerator(DefaultProviderConfig(), cfg, spec)
+	code, err := gen.GenerateMoveStateCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`if req.SourceTypeName != "kubernetes_\"widget\"" {`,
+		`{From: "spec\\ports", To: "spec.ports", Key: "\"name\"", Unwrap: false}`,
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("expected %s in:\n%s", expected, code)
+		}
+	}
+}
+
+func TestValidateMoveFrom(t *testing.T) {
+	r := ResourceConfig{Name: "kubernetes_widget_v1", MoveFrom: []MoveFromConfig{
+		{ResourceType: "kubernetes_widget", Moves: []AttributeMoveConfig{{From: "spec", To: "spec", Unwrap: true}}},
+	}}
+	if err := validateMoveFrom(r); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	r.MoveFrom[0].Moves = append(r.MoveFrom[0].Moves, AttributeMoveConfig{From: "metadata", To: "metadata"})
+	if err := validateMoveFrom(r); err == nil || !strings.Contains(err.Error(), "must set unwrap or key") {
+		t.Errorf("expected an error for a move to the same path got %v", err)
+	}
+}
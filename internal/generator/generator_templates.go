@@ -37,6 +37,18 @@ var autocrudTemplate string
 //go:embed templates/resource_autocrud_hooks.go.tpl
 var autocrudHooksTemplate string
 
+//go:embed templates/resource_state_upgrade.go.tpl
+var stateUpgradeTemplate string
+
+//go:embed templates/resource_move_state.go.tpl
+var moveStateTemplate string
+
+//go:embed templates/resource_cel_validation.go.tpl
+var celValidationTemplate string
+
+//go:embed templates/resource_validators_test.go.tpl
+var validatorTestsTemplate string
+
 //go:embed templates/resource_model.go.tpl
 var modelTemplate string
 
@@ -46,15 +58,35 @@ var modelFieldsTemplate string
 //go:embed templates/model_field.tpl
 var modelFieldTemplate string
 
-func renderTemplate(path string, r any) string {
+// templates contains every template, it is used to detect changes to the generator
+var templates = []string{
+	attributesTemplate,
+	attributeTemplate,
+	schemaTemplate,
+	schemaFunctionTemplate,
+	resourceTemplate,
+	resourcesListTemplate,
+	crudStubsTemplate,
+	autocrudTemplate,
+	autocrudHooksTemplate,
+	stateUpgradeTemplate,
+	moveStateTemplate,
+	celValidationTemplate,
+	validatorTestsTemplate,
+	modelTemplate,
+	modelFieldsTemplate,
+	modelFieldTemplate,
+}
+
+func renderTemplate(path string, r any) (string, error) {
 	tpl, err := template.New("").Parse(path)
 	if err != nil {
-		panic(fmt.Sprintf("could not parse template: %v", err))
+		return "", fmt.Errorf("could not parse template: %v", err)
 	}
 	var buf bytes.Buffer
 	err = tpl.Execute(&buf, r)
 	if err != nil {
-		panic(fmt.Sprintf("error executing template: %v", err))
+		return "", fmt.Errorf("error executing template: %v", err)
 	}
-	return buf.String()
+	return buf.String(), nil
 }
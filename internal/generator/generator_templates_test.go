@@ -0,0 +1,22 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import "testing"
+
+func TestRenderTemplateError(t *testing.T) {
+	testCases := map[string]string{
+		"parse":   "{{ .Name ",
+		"execute": "{{ .MissingField }}",
+	}
+
+	for name, tpl := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := renderTemplate(tpl, SchemaGenerator{Name: "test"})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -10,9 +10,11 @@ const (
 	Int64AttributeType        = "Int64Attribute"
 	MapAttributeType          = "MapAttribute"
 	ListAttributeType         = "ListAttribute"
+	SetAttributeType          = "SetAttribute"
 	ObjectAttributeType       = "ObjectAttribute"
 	SingleNestedAttributeType = "SingleNestedAttribute"
 	ListNestedAttributeType   = "ListNestedAttribute"
+	SetNestedAttributeType    = "SetNestedAttribute"
 	DynamicAttributeType      = "DynamicAttribute"
 )
 
@@ -39,6 +41,20 @@ const (
 	NumberPlanModifierType = "Number"
 	Int64PlanModifierType  = "Int64"
 	ObjectPlanModifierType = "Object"
+	ListPlanModifierType   = "List"
+	SetPlanModifierType    = "Set"
+	MapPlanModifierType    = "Map"
+)
+
+const (
+	BoolValidatorType   = "Bool"
+	StringValidatorType = "String"
+	NumberValidatorType = "Number"
+	Int64ValidatorType  = "Int64"
+	ObjectValidatorType = "Object"
+	ListValidatorType   = "List"
+	SetValidatorType    = "Set"
+	MapValidatorType    = "Map"
 )
 
 const (
@@ -47,4 +63,7 @@ const (
 	NumberPlanModifierPackage = "numberplanmodifier"
 	Int64PlanModifierPackage  = "int64planmodifier"
 	ObjectPlanModifierPackage = "objectplanmodifier"
+	ListPlanModifierPackage   = "listplanmodifier"
+	SetPlanModifierPackage    = "setplanmodifier"
+	MapPlanModifierPackage    = "mapplanmodifier"
 )
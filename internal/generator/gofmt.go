@@ -4,23 +4,154 @@
 package generator
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"go/format"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 )
 
-// WriteFormattedSourceFile runs Go code through format before writing to a file
-func WriteFormattedSourceFile(wd, path string, contents string) error {
+// timestampPattern matches the line in the generated code header that
+// contains the time the code was generated
+var timestampPattern = regexp.MustCompile(`(?m)^// This code was written by a robot on .*$`)
+
+// generatedPattern matches the comment that marks a file as generated,
+// see https://pkg.go.dev/cmd/go#hdr-Generate_Go_files_by_processing_source
+var generatedPattern = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// IsGeneratedSource returns true if the Go source has the generated code header
+func IsGeneratedSource(contents []byte) bool {
+	return generatedPattern.Match(contents)
+}
+
+// scaffoldedPattern matches the comment that marks a file as scaffolded,
+// scaffolded files are written once and then belong to the user
+var scaffoldedPattern = regexp.MustCompile(`(?m)^// Code (scaffolded|generated) by \S+; THIS FILE IS FOR YOU TO EDIT\.?$`)
+
+// IsScaffoldedSource returns true if the Go source has the scaffolded code header
+func IsScaffoldedSource(contents []byte) bool {
+	return scaffoldedPattern.Match(contents)
+}
+
+// WritePolicy controls what happens when a file being written already exists
+type WritePolicy int
+
+const (
+	// Overwrite replaces the existing file, unless it has been scaffolded
+	Overwrite WritePolicy = iota
+
+	// CreateOnly leaves the existing file untouched
+	CreateOnly
+
+	// MergeNewMethods adds the methods that are missing from the existing file
+	MergeNewMethods
+)
+
+func (p WritePolicy) String() string {
+	switch p {
+	case Overwrite:
+		return "overwrite"
+	case CreateOnly:
+		return "create-only"
+	case MergeNewMethods:
+		return "merge-new-methods"
+	}
+	return fmt.Sprintf("WritePolicy(%d)", int(p))
+}
+
+// WriteFormattedSourceFile runs Go code through format before writing to a
+// file, the policy controls what happens when the file already exists. It
+// returns false if the existing file was left unchanged.
+func WriteFormattedSourceFile(wd, path string, contents string, policy WritePolicy) (bool, error) {
 	src, err := format.Source([]byte(contents))
 	outputPath := filepath.Join(wd, path)
 	if err != nil {
-		// if there's an error, write the unformattable Go code to the file so we cans see what broke
-		writeErr := os.WriteFile(outputPath, []byte(contents), os.ModePerm)
+		// if there's an error, write the unformattable Go code to a file so we
+		// can see what broke, without replacing a file that belongs to the user
+		brokenPath := unformattedSourcePath(outputPath, policy)
+		writeErr := os.WriteFile(brokenPath, []byte(contents), os.ModePerm)
 		if writeErr != nil {
-			return writeErr
+			return false, writeErr
+		}
+		return brokenPath == outputPath, fmt.Errorf("failed to format Go file %q, wrote the unformatted code to %q: %v", outputPath, brokenPath, err)
+	}
+
+	src, changed, err := applyWritePolicy(outputPath, src, policy)
+	if err != nil || !changed {
+		return false, err
+	}
+	return true, os.WriteFile(outputPath, src, os.ModePerm)
+}
+
+// unformattedSourcePath returns the path to write code that could not be
+// formatted to. Only files that the policy would overwrite are replaced,
+// otherwise the code is written next to the file with a .broken suffix.
+func unformattedSourcePath(outputPath string, policy WritePolicy) string {
+	existing, err := os.ReadFile(outputPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return outputPath
+	}
+	if err != nil || policy != Overwrite || IsScaffoldedSource(existing) {
+		return outputPath + ".broken"
+	}
+	return outputPath
+}
+
+// CheckFormattedSourceFile runs Go code through format and compares it to the
+// file on disk, ignoring the generated timestamp. It returns false if the file
+// does not exist or its contents are stale according to the policy.
+func CheckFormattedSourceFile(wd, path string, contents string, policy WritePolicy) (bool, error) {
+	outputPath := filepath.Join(wd, path)
+	src, err := format.Source([]byte(contents))
+	if err != nil {
+		return false, fmt.Errorf("failed to format Go file %q: %v", outputPath, err)
+	}
+	if policy == Overwrite {
+		return CheckFile(wd, path, src)
+	}
+	_, changed, err := applyWritePolicy(outputPath, src, policy)
+	return !changed, err
+}
+
+// applyWritePolicy returns the contents the file should have after it is
+// written and whether they differ from the existing file
+func applyWritePolicy(outputPath string, src []byte, policy WritePolicy) ([]byte, bool, error) {
+	existing, err := os.ReadFile(outputPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return src, true, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	switch policy {
+	case Overwrite:
+		if IsScaffoldedSource(existing) && !IsScaffoldedSource(src) {
+			return nil, false, fmt.Errorf("refusing to overwrite scaffolded file %q", outputPath)
 		}
-		return fmt.Errorf("failed to format Go file %q", outputPath)
+		return src, true, nil
+	case CreateOnly:
+		return existing, false, nil
+	case MergeNewMethods:
+		merged, added, err := mergeNewMethods(existing, src)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to merge methods into %q: %v", outputPath, err)
+		}
+		return merged, len(added) > 0, nil
+	}
+	return nil, false, fmt.Errorf("unknown write policy %v", policy)
+}
+
+// CheckFile compares contents to the file on disk, ignoring the generated
+// timestamp. It returns false if the file does not exist or its contents are stale.
+func CheckFile(wd, path string, contents []byte) (bool, error) {
+	existing, err := os.ReadFile(filepath.Join(wd, path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
 	}
-	return os.WriteFile(outputPath, src, os.ModePerm)
+	return bytes.Equal(timestampPattern.ReplaceAll(existing, nil), timestampPattern.ReplaceAll(contents, nil)), nil
 }
@@ -0,0 +1,181 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckFormattedSourceFile(t *testing.T) {
+	wd := t.TempDir()
+	existing := "// This code was written by a robot on Jan 02, 2006 15:04:05 UTC.\n\npackage test\n"
+	err := os.WriteFile(filepath.Join(wd, "test_gen.go"), []byte(existing), os.ModePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		filename string
+		contents string
+		expected bool
+	}{
+		"up to date": {
+			filename: "test_gen.go",
+			contents: "// This code was written by a robot on Mar 04, 2024 10:00:00 UTC.\n\npackage   test",
+			expected: true,
+		},
+		"stale": {
+			filename: "test_gen.go",
+			contents: "// This code was written by a robot on Mar 04, 2024 10:00:00 UTC.\n\npackage other",
+			expected: false,
+		},
+		"missing": {
+			filename: "missing_gen.go",
+			contents: "package test",
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := CheckFormattedSourceFile(wd, tc.filename, tc.contents, Overwrite)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.expected {
+				t.Fatalf("expected %t got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsGeneratedSource(t *testing.T) {
+	testCases := map[string]struct {
+		contents   string
+		generated  bool
+		scaffolded bool
+	}{
+		"generated": {
+			contents:  "// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.\n\npackage test\n",
+			generated: true,
+		},
+		"scaffolded": {
+			contents:   "// Code scaffolded by hashicorp/terraform-plugin-codegen-kubernetes; THIS FILE IS FOR YOU TO EDIT.\n\npackage test\n",
+			scaffolded: true,
+		},
+		"old hooks header": {
+			contents:   "// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; THIS FILE IS FOR YOU TO EDIT\n\npackage test\n",
+			scaffolded: true,
+		},
+		"hand written": {
+			contents: "package test\n",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if IsGeneratedSource([]byte(tc.contents)) != tc.generated {
+				t.Fatalf("expected generated to be %v", tc.generated)
+			}
+			if IsScaffoldedSource([]byte(tc.contents)) != tc.scaffolded {
+				t.Fatalf("expected scaffolded to be %v", tc.scaffolded)
+			}
+		})
+	}
+}
+
+func TestWriteFormattedSourceFilePolicy(t *testing.T) {
+	scaffolded := "// Code scaffolded by hashicorp/terraform-plugin-codegen-kubernetes; THIS FILE IS FOR YOU TO EDIT.\n\npackage test\n\nfunc A() {}\n"
+	testCases := map[string]struct {
+		existing string
+		policy   WritePolicy
+		contents string
+		expected string
+		broken   string
+		written  bool
+		err      bool
+	}{
+		"overwrite": {
+			existing: "package test\n\nfunc A() {}\n",
+			policy:   Overwrite,
+			contents: "package test\n",
+			expected: "package test\n",
+			written:  true,
+		},
+		"overwrite scaffolded": {
+			policy:   Overwrite,
+			contents: "// Code generated by test; DO NOT EDIT.\n\npackage test\n",
+			expected: scaffolded,
+			err:      true,
+		},
+		"create only": {
+			policy:   CreateOnly,
+			contents: "package test\n\nfunc B() {}\n",
+			expected: scaffolded,
+		},
+		"merge new methods": {
+			policy:   MergeNewMethods,
+			contents: "package test\n\nfunc A() { panic(1) }\n\nfunc B() {}\n",
+			expected: scaffolded + "\nfunc B() {}\n",
+			written:  true,
+		},
+		"overwrite unformattable": {
+			existing: "package test\n",
+			policy:   Overwrite,
+			contents: "package test\n\nfunc {",
+			expected: "package test\n\nfunc {",
+			written:  true,
+			err:      true,
+		},
+		"create only unformattable": {
+			policy:   CreateOnly,
+			contents: "package test\n\nfunc {",
+			expected: scaffolded,
+			broken:   "package test\n\nfunc {",
+			err:      true,
+		},
+		"merge new methods unformattable": {
+			policy:   MergeNewMethods,
+			contents: "package test\n\nfunc {",
+			expected: scaffolded,
+			broken:   "package test\n\nfunc {",
+			err:      true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			wd := t.TempDir()
+			existing := tc.existing
+			if existing == "" {
+				existing = scaffolded
+			}
+			err := os.WriteFile(filepath.Join(wd, "test.go"), []byte(existing), os.ModePerm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			written, err := WriteFormattedSourceFile(wd, "test.go", tc.contents, tc.policy)
+			if tc.err != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if written != tc.written {
+				t.Fatalf("expected written to be %v", tc.written)
+			}
+			actual, err := os.ReadFile(filepath.Join(wd, "test.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != tc.expected {
+				t.Fatalf("expected %q got %q", tc.expected, string(actual))
+			}
+			broken, err := os.ReadFile(filepath.Join(wd, "test.go.broken"))
+			if err != nil && tc.broken != "" {
+				t.Fatal(err)
+			}
+			if string(broken) != tc.broken {
+				t.Fatalf("expected broken file to be %q got %q", tc.broken, string(broken))
+			}
+		})
+	}
+}
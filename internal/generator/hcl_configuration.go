@@ -5,6 +5,9 @@ package generator
 
 import (
 	"fmt"
+	"log/slog"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/hcl/v2/hclsimple"
@@ -15,10 +18,113 @@ const defaultTimeoutDuration = "20m"
 
 // GeneratorConfig is the top level code generator configuration
 type GeneratorConfig struct {
+	Provider   *ProviderConfig    `hcl:"provider,block"`
 	Resources  []ResourceConfig   `hcl:"resource,block"`
 	DataSource []DataSourceConfig `hcl:"data,block"`
 }
 
+// ProviderConfig configures the provider that the generated code is part of
+type ProviderConfig struct {
+	// ModulePath is the Go module path of the provider, e.g. github.com/hashicorp/terraform-provider-kubernetes
+	ModulePath string `hcl:"module_path,optional"`
+
+	// PackageRoot is the directory in the provider module that contains the
+	// resource packages, e.g. internal/framework/provider
+	PackageRoot string `hcl:"package_root,optional"`
+
+	// ClientPackage is the package in the provider module that contains the
+	// KubernetesClientGetter interface, e.g. internal/framework/provider/client
+	ClientPackage string `hcl:"client_package,optional"`
+
+	// OutputDir is the directory the resources list source file is written to
+	OutputDir string `hcl:"output_dir,optional"`
+
+	// PackageName is the name of the Go package for the resources list source file
+	PackageName string `hcl:"package_name,optional"`
+
+	// LicenseHeader is added as a comment to the top of every generated file
+	LicenseHeader string `hcl:"license_header,optional"`
+
+	// PackageImportPathOverride replaces the import path of the directory
+	// containing the resource packages, it is set by the -provider-package
+	// flag and does not change the import path of the client package
+	PackageImportPathOverride string
+}
+
+// DefaultProviderConfig returns the configuration for terraform-provider-kubernetes
+func DefaultProviderConfig() ProviderConfig {
+	return ProviderConfig{
+		ModulePath:    "github.com/hashicorp/terraform-provider-kubernetes",
+		PackageRoot:   "internal/framework/provider",
+		ClientPackage: "internal/framework/provider/client",
+		OutputDir:     "./provider",
+		PackageName:   "provider",
+	}
+}
+
+// PackageImportPath returns the import path of the directory containing the resource packages
+func (p ProviderConfig) PackageImportPath() string {
+	if p.PackageImportPathOverride != "" {
+		return p.PackageImportPathOverride
+	}
+	return path.Join(p.ModulePath, p.PackageRoot)
+}
+
+// ClientImportPath returns the import path of the client package
+func (p ProviderConfig) ClientImportPath() string {
+	return path.Join(p.ModulePath, p.ClientPackage)
+}
+
+// LicenseComment returns the license header as a Go comment followed by a blank line
+func (p ProviderConfig) LicenseComment() string {
+	if p.LicenseHeader == "" {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(p.LicenseHeader, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("// "+l, " ")
+	}
+	return strings.Join(lines, "\n") + "\n\n"
+}
+
+// ResolveProviderConfig combines the provider blocks of the supplied configurations
+// with the defaults. The provider block can be set in any of the configuration files
+// but if it is set in more than one they must be the same.
+func ResolveProviderConfig(configs []GeneratorConfig) (ProviderConfig, error) {
+	var providerConfig *ProviderConfig
+	for _, c := range configs {
+		if c.Provider == nil {
+			continue
+		}
+		if providerConfig != nil && *providerConfig != *c.Provider {
+			return ProviderConfig{}, fmt.Errorf("provider block is set to different values in multiple configuration files")
+		}
+		providerConfig = c.Provider
+	}
+
+	p := DefaultProviderConfig()
+	if providerConfig == nil {
+		return p, nil
+	}
+	if providerConfig.ModulePath != "" {
+		p.ModulePath = providerConfig.ModulePath
+	}
+	if providerConfig.PackageRoot != "" {
+		p.PackageRoot = providerConfig.PackageRoot
+	}
+	if providerConfig.ClientPackage != "" {
+		p.ClientPackage = providerConfig.ClientPackage
+	}
+	if providerConfig.OutputDir != "" {
+		p.OutputDir = providerConfig.OutputDir
+	}
+	if providerConfig.PackageName != "" {
+		p.PackageName = providerConfig.PackageName
+	}
+	p.LicenseHeader = providerConfig.LicenseHeader
+	return p, nil
+}
+
 // ResourceConfig configures code generation for a Terraform resource
 type ResourceConfig struct {
 	// Name is the terraform name of this resource
@@ -40,26 +146,43 @@ type ResourceConfig struct {
 	// Description is a Markdown description for the resource
 	Description string `hcl:"description"`
 
+	// Attributes overrides the generated schema for individual attributes
+	Attributes []AttributeConfig `hcl:"attribute,block"`
+
 	// IgnoredAttributes is a list of attribute paths to omit from the resource
+	//
+	// Deprecated: use an attribute block with ignored = true
 	IgnoredAttributes []string `hcl:"ignored_attributes,optional"`
 
 	// RequiredAttributes is a list of attribute paths to mark as required in the schema
+	//
+	// Deprecated: use an attribute block with required = true
 	RequiredAttributes []string `hcl:"required_attributes,optional"`
 
 	// ComputedAttributes is a list of attribute paths to mark as computed in the schema
+	//
+	// Deprecated: use an attribute block with computed = true
 	ComputedAttributes []string `hcl:"computed_attributes,optional"`
 
 	// SensitiveAttributes is a list of attribute paths to mark as sensitive in the schema
+	//
+	// Deprecated: use an attribute block with sensitive = true
 	SensitiveAttributes []string `hcl:"sensitive_attributes,optional"`
 
 	// ImmutableAttributes is a list of attribute paths to mark as requiring a forced
 	// replacement if changed in the schema
+	//
+	// Deprecated: use an attribute block with immutable = true
 	ImmutableAttributes []string `hcl:"immutable_attributes,optional"`
 
 	// CustomAttributes is a list of non OpenAPI/backend attributes to generate using types.Dynamic in the schema/model
 	// but would be handled by the developer in a hook method
 	CustomAttributes []string `hcl:"custom_attributes,optional"`
 
+	// AcknowledgedBreakingChanges is a list of attribute paths with breaking schema
+	// changes that have been reviewed and are safe to generate
+	AcknowledgedBreakingChanges []string `hcl:"acknowledged_breaking_changes,optional"`
+
 	// Generate controls generator specific options
 	Generate GenerateConfig `hcl:"generate,block"`
 
@@ -68,6 +191,67 @@ type ResourceConfig struct {
 
 	// Disabled tells the generator to skip this configuration
 	Disabled bool `hcl:"disabled,optional"`
+
+	// SchemaVersion is the version of the generated schema, it should be
+	// incremented when a regenerated schema changes shape
+	SchemaVersion int64 `hcl:"schema_version,optional"`
+
+	// StateUpgrades describes prior schema versions and how to upgrade
+	// state from them to the current schema version
+	StateUpgrades []StateUpgradeConfig `hcl:"state_upgrades,block"`
+
+	// MoveFrom describes other resource types, such as the SDKv2 implementation
+	// of this resource, that state can be moved from using a moved block
+	MoveFrom []MoveFromConfig `hcl:"move_from,block"`
+}
+
+// AttributeConfig overrides how an attribute is generated. The path can be
+// a pattern, if more than one block matches an attribute the options set in
+// later blocks take precedence.
+type AttributeConfig struct {
+	// Path is the attribute path, e.g. spec.replicas or spec.ports[*].name
+	Path string `hcl:"path,label"`
+
+	// Description replaces the description from the OpenAPI spec
+	Description *string `hcl:"description,optional"`
+
+	// Default is the value used when the attribute is not set in the configuration,
+	// it is only supported for bool, string, number and int64 attributes
+	Default *string `hcl:"default,optional"`
+
+	// Validators is a list of Go expressions for additional validators, e.g. int64validator.AtLeast(0)
+	Validators []string `hcl:"validators,optional"`
+
+	// PlanModifiers is a list of Go expressions for additional plan modifiers,
+	// e.g. stringplanmodifier.UseStateForUnknown()
+	PlanModifiers []string `hcl:"plan_modifiers,optional"`
+
+	// Imports is a list of import paths needed by the validators and plan modifiers,
+	// the framework validator and plan modifier packages are imported automatically
+	Imports []string `hcl:"imports,optional"`
+
+	// Ignored omits the attribute from the resource
+	Ignored *bool `hcl:"ignored,optional"`
+
+	// Required marks the attribute as required in the schema
+	Required *bool `hcl:"required,optional"`
+
+	// Computed marks the attribute as computed in the schema
+	Computed *bool `hcl:"computed,optional"`
+
+	// Sensitive marks the attribute as sensitive in the schema
+	Sensitive *bool `hcl:"sensitive,optional"`
+
+	// Immutable marks the attribute as requiring a forced replacement if changed
+	Immutable *bool `hcl:"immutable,optional"`
+
+	// DeprecationMessage marks the attribute as deprecated with this message
+	DeprecationMessage string `hcl:"deprecation_message,optional"`
+
+	// Rename is the name of the attribute in the schema and model, the name
+	// in the Kubernetes manifest is unchanged. A rename made with a schema
+	// version bump needs a move block in the state upgrade to the new name.
+	Rename string `hcl:"rename,optional"`
 }
 
 // DataSourceConfig configures code generation for a Terraform data source
@@ -88,6 +272,53 @@ type TerraformPluginGenOpenAPIConfig struct {
 	ReadPath string `hcl:"read_path"`
 }
 
+// StateUpgradeConfig describes a prior schema version and the attribute
+// moves needed to upgrade its state to the current schema
+type StateUpgradeConfig struct {
+	// Version is the prior schema version this upgrade applies to
+	Version int64 `hcl:"version"`
+
+	// OpenAPIConfig points to the OpenAPI specification the prior schema was generated from
+	OpenAPIConfig *TerraformPluginGenOpenAPIConfig `hcl:"openapi,block"`
+
+	// Snapshot is the filename of a framework IR JSON snapshot of the prior schema,
+	// it can be used instead of an OpenAPI specification
+	Snapshot string `hcl:"snapshot,optional"`
+
+	// Moves is a list of attributes that have moved since the prior schema version
+	Moves []AttributeMoveConfig `hcl:"move,block"`
+}
+
+// AttributeMoveConfig describes an attribute whose path has changed
+// between schema versions
+type AttributeMoveConfig struct {
+	// From is the attribute path in the prior schema, e.g. spec.ports
+	From string `hcl:"from"`
+
+	// To is the attribute path in the current schema
+	To string `hcl:"to"`
+
+	// Key is the name of the attribute to key the map by when a list of
+	// objects has become a map
+	Key string `hcl:"key,optional"`
+
+	// Unwrap converts a list containing a single object into that object, e.g.
+	// when moving from an SDKv2 block with MaxItems: 1 to a nested attribute
+	Unwrap bool `hcl:"unwrap,optional"`
+}
+
+// MoveFromConfig describes a source resource type that state can be moved from
+type MoveFromConfig struct {
+	// ResourceType is the terraform name of the source resource, e.g. kubernetes_config_map
+	ResourceType string `hcl:"resource_type,label"`
+
+	// SchemaVersion is the schema version of the source resource state
+	SchemaVersion int64 `hcl:"schema_version,optional"`
+
+	// Moves is a list of attributes whose path differs in the source resource
+	Moves []AttributeMoveConfig `hcl:"move,block"`
+}
+
 // CRUDAutoOptions configures options for the autocrud template
 type CRUDAutoOptions struct {
 	WaitForDeletion bool   `hcl:"wait_for_deletion,optional"`
@@ -125,13 +356,53 @@ type Timeouts struct {
 // GenerateConfig configures the options for what we should generate
 type GenerateConfig struct {
 	Schema          bool             `hcl:"schema,optional"`
-	Overrides       bool             `hcl:"overrides,optional"`
 	Model           bool             `hcl:"model,optional"`
 	CRUDAuto        bool             `hcl:"autocrud,optional"`
 	CRUDAutoOptions *CRUDAutoOptions `hcl:"autocrud_options,block"`
 	CRUDStubs       bool             `hcl:"crud_stubs,optional"`
 	Timeouts        *Timeouts        `hcl:"timeouts,block"`
 	GenAIValidation bool             `hcl:"gen_ai_validation,optional"`
+	CELValidation   bool             `hcl:"cel_validation,optional"`
+	ValidatorTests  bool             `hcl:"validator_tests,optional"`
+
+	// Overrides is accepted so older configuration files still parse
+	//
+	// Deprecated: it has no effect, use attribute blocks
+	Overrides bool `hcl:"overrides,optional"`
+}
+
+// translateAttributeLists converts the deprecated attribute lists into attribute
+// blocks. The translated blocks come first so explicit attribute blocks take precedence.
+func translateAttributeLists(r ResourceConfig) ResourceConfig {
+	attributes := []AttributeConfig{}
+	for _, l := range []struct {
+		name  string
+		paths []string
+		set   func(*AttributeConfig)
+	}{
+		{"ignored_attributes", r.IgnoredAttributes, func(a *AttributeConfig) { a.Ignored = ptr(true) }},
+		{"required_attributes", r.RequiredAttributes, func(a *AttributeConfig) { a.Required = ptr(true) }},
+		{"computed_attributes", r.ComputedAttributes, func(a *AttributeConfig) { a.Computed = ptr(true) }},
+		{"sensitive_attributes", r.SensitiveAttributes, func(a *AttributeConfig) { a.Sensitive = ptr(true) }},
+		{"immutable_attributes", r.ImmutableAttributes, func(a *AttributeConfig) { a.Immutable = ptr(true) }},
+	} {
+		if len(l.paths) == 0 {
+			continue
+		}
+		slog.Warn(fmt.Sprintf("%s is deprecated, use attribute blocks instead", l.name), "resource", r.Name)
+		for _, p := range l.paths {
+			a := AttributeConfig{Path: p}
+			l.set(&a)
+			attributes = append(attributes, a)
+		}
+	}
+	r.Attributes = append(attributes, r.Attributes...)
+	r.IgnoredAttributes = nil
+	r.RequiredAttributes = nil
+	r.ComputedAttributes = nil
+	r.SensitiveAttributes = nil
+	r.ImmutableAttributes = nil
+	return r
 }
 
 func validateTimeoutDurations(r ResourceConfig) (ResourceConfig, error) {
@@ -173,6 +444,56 @@ func validateTimeoutDurations(r ResourceConfig) (ResourceConfig, error) {
 	return r, nil
 }
 
+func validateStateUpgrades(r ResourceConfig) error {
+	versions := map[int64]struct{}{}
+	for _, u := range r.StateUpgrades {
+		if u.Version < 0 || u.Version >= r.SchemaVersion {
+			return fmt.Errorf("state upgrade version %d for %s must be less than schema_version %d", u.Version, r.Name, r.SchemaVersion)
+		}
+		if _, ok := versions[u.Version]; ok {
+			return fmt.Errorf("duplicate state upgrade version %d for %s", u.Version, r.Name)
+		}
+		versions[u.Version] = struct{}{}
+		if (u.OpenAPIConfig == nil) == (u.Snapshot == "") {
+			return fmt.Errorf("state upgrade version %d for %s must set exactly one of openapi or snapshot", u.Version, r.Name)
+		}
+		if err := validateMoves(u.Moves); err != nil {
+			return fmt.Errorf("state upgrade version %d for %s: %v", u.Version, r.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateMoves checks the move blocks have paths and change something
+func validateMoves(moves []AttributeMoveConfig) error {
+	for _, m := range moves {
+		if m.From == "" || m.To == "" {
+			return fmt.Errorf("move from %q to %q must set both paths", m.From, m.To)
+		}
+		if m.From == m.To && !m.Unwrap && m.Key == "" {
+			return fmt.Errorf("move from %q to the same path must set unwrap or key", m.From)
+		}
+	}
+	return nil
+}
+
+func validateMoveFrom(r ResourceConfig) error {
+	resourceTypes := map[string]struct{}{}
+	for _, m := range r.MoveFrom {
+		if m.ResourceType == r.Name {
+			return fmt.Errorf("%s cannot move state from itself, use state_upgrades instead", r.Name)
+		}
+		if _, ok := resourceTypes[m.ResourceType]; ok {
+			return fmt.Errorf("duplicate move_from block %q for %s", m.ResourceType, r.Name)
+		}
+		resourceTypes[m.ResourceType] = struct{}{}
+		if err := validateMoves(m.Moves); err != nil {
+			return fmt.Errorf("move_from block %q for %s: %v", m.ResourceType, r.Name, err)
+		}
+	}
+	return nil
+}
+
 // ParseHCLConfig parses the .hcl configuraiton file and
 // produces a GeneratorConfig
 func ParseHCLConfig(filename string) (GeneratorConfig, error) {
@@ -183,10 +504,25 @@ func ParseHCLConfig(filename string) (GeneratorConfig, error) {
 	}
 
 	for i, r := range config.Resources {
-		rc, err := validateTimeoutDurations(r)
+		if r.Generate.Overrides {
+			slog.Warn("overrides is deprecated and has no effect", "resource", r.Name)
+		}
+		rc, err := validateTimeoutDurations(translateAttributeLists(r))
 		if err != nil {
 			return config, err
 		}
+		if err := validateStateUpgrades(rc); err != nil {
+			return config, err
+		}
+		if err := validateMoveFrom(rc); err != nil {
+			return config, err
+		}
+		if err := validateAttributes(rc); err != nil {
+			return config, err
+		}
+		if rc.Generate.CELValidation && !rc.Generate.Model {
+			return config, fmt.Errorf("cel_validation for %s requires model to be generated", rc.Name)
+		}
 		config.Resources[i] = rc
 	}
 
@@ -195,13 +531,32 @@ func ParseHCLConfig(filename string) (GeneratorConfig, error) {
 
 // Checks whether hooks are used to prevent file from being generated if block is empty or all set to false.
 func (h *Hooks) IsEmpty() bool {
-	if h != nil {
-		if h.BeforeHook != nil {
-			return !(h.BeforeHook.Create || h.BeforeHook.Read || h.BeforeHook.Update || h.BeforeHook.Delete)
-		}
-		if h.AfterHook != nil {
-			return !(h.AfterHook.Create || h.AfterHook.Read || h.AfterHook.Update || h.AfterHook.Delete)
+	return len(h.EnabledMethods()) == 0
+}
+
+// EnabledMethods returns the names of the hook methods that are enabled, e.g. BeforeCreate
+func (h *Hooks) EnabledMethods() []string {
+	methods := []string{}
+	if h == nil {
+		return methods
+	}
+	if h.BeforeHook != nil {
+		methods = appendHookMethods(methods, "Before", h.BeforeHook.Create, h.BeforeHook.Read, h.BeforeHook.Update, h.BeforeHook.Delete)
+	}
+	if h.AfterHook != nil {
+		methods = appendHookMethods(methods, "After", h.AfterHook.Create, h.AfterHook.Read, h.AfterHook.Update, h.AfterHook.Delete)
+	}
+	return methods
+}
+
+func appendHookMethods(methods []string, prefix string, create, read, update, delete bool) []string {
+	for i, enabled := range []bool{create, read, update, delete} {
+		if enabled {
+			methods = append(methods, prefix+hookOperations[i])
 		}
 	}
-	return true
+	return methods
 }
+
+// hookOperations are the operations that can have hooks, in the order they are configured
+var hookOperations = []string{"Create", "Read", "Update", "Delete"}
@@ -0,0 +1,97 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveProviderConfig(t *testing.T) {
+	p, err := ResolveProviderConfig([]GeneratorConfig{{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p != DefaultProviderConfig() {
+		t.Fatalf("expected %v got %v", DefaultProviderConfig(), p)
+	}
+
+	p, err = ResolveProviderConfig([]GeneratorConfig{
+		{},
+		{Provider: &ProviderConfig{ModulePath: "example.com/provider", ClientPackage: "client"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "example.com/provider/client"
+	if p.ClientImportPath() != expected {
+		t.Fatalf("expected %q got %q", expected, p.ClientImportPath())
+	}
+	expected = "example.com/provider/internal/framework/provider"
+	if p.PackageImportPath() != expected {
+		t.Fatalf("expected %q got %q", expected, p.PackageImportPath())
+	}
+
+	_, err = ResolveProviderConfig([]GeneratorConfig{
+		{Provider: &ProviderConfig{ModulePath: "example.com/a"}},
+		{Provider: &ProviderConfig{ModulePath: "example.com/b"}},
+	})
+	if err == nil {
+		t.Fatalf("expected error for conflicting provider blocks")
+	}
+}
+
+func TestParseHCLConfigDeprecatedAttributeLists(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "generate.hcl")
+	config := `resource "kubernetes_widget" {
+  package                = "widget"
+  api_version            = "v1"
+  kind                   = "Widget"
+  description            = "Widgets"
+  output_filename_prefix = "widget"
+
+  ignored_attributes   = ["metadata.uid"]
+  required_attributes  = ["spec.size"]
+  immutable_attributes = ["spec.size"]
+
+  attribute "spec.size" {
+    required = false
+  }
+
+  openapi {
+    filename    = "openapi.json"
+    create_path = "/api/v1/widgets"
+    read_path   = "/api/v1/widgets/{name}"
+  }
+
+  generate {
+    schema    = true
+    overrides = true
+  }
+}
+`
+	if err := os.WriteFile(filename, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ParseHCLConfig(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := c.Resources[0]
+	if len(r.Attributes) != 4 {
+		t.Fatalf("expected the lists to be translated into attribute blocks got %v", r.Attributes)
+	}
+	if a := r.attributeConfig("metadata.uid"); a.Ignored == nil || !*a.Ignored {
+		t.Errorf("expected metadata.uid to be ignored")
+	}
+	a := r.attributeConfig("spec.size")
+	if a.Immutable == nil || !*a.Immutable {
+		t.Errorf("expected spec.size to be immutable")
+	}
+	if a.Required == nil || *a.Required {
+		t.Errorf("expected the attribute block to take precedence over required_attributes")
+	}
+}
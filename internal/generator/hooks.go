@@ -0,0 +1,93 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"sort"
+)
+
+// isHookMethod returns true if name is the name of a hook method, e.g. AfterRead
+func isHookMethod(name string) bool {
+	for _, op := range hookOperations {
+		if name == "Before"+op || name == "After"+op {
+			return true
+		}
+	}
+	return false
+}
+
+// hookMethodParams is the number of parameters of the methods in the autocrud hook interfaces
+const hookMethodParams = 5
+
+// declaredMethods returns the names of the methods declared on the receiver
+// type in the source and their number of parameters
+func declaredMethods(src []byte, receiver string) (map[string]int, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	methods := map[string]int{}
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil {
+			continue
+		}
+		if funcDeclName(fn) == receiver+"."+fn.Name.Name {
+			methods[fn.Name.Name] = fn.Type.Params.NumFields()
+		}
+	}
+	return methods, nil
+}
+
+// MissingHookMethods returns the enabled hook methods that are not declared
+// on the resource type in the existing hooks source
+func (g *ResourceGenerator) MissingHookMethods(existing []byte) ([]string, error) {
+	declared, err := declaredMethods(existing, g.ResourceConfig.Kind)
+	if err != nil {
+		return nil, err
+	}
+	missing := []string{}
+	for _, m := range g.hooks().EnabledMethods() {
+		if _, ok := declared[m]; !ok {
+			missing = append(missing, m)
+		}
+	}
+	return missing, nil
+}
+
+// UnusedHookMethods returns the hook methods declared on the resource type in
+// the existing hooks source that are never called because they are not enabled
+// and do not have the signature of the autocrud hook interfaces. Enabled hooks
+// with the wrong signature fail to compile so are not included.
+func (g *ResourceGenerator) UnusedHookMethods(existing []byte) ([]string, error) {
+	declared, err := declaredMethods(existing, g.ResourceConfig.Kind)
+	if err != nil {
+		return nil, err
+	}
+	enabled := g.hooks().EnabledMethods()
+	unused := []string{}
+	for m, params := range declared {
+		if isHookMethod(m) && !slices.Contains(enabled, m) && params != hookMethodParams {
+			unused = append(unused, m)
+		}
+	}
+	sort.Strings(unused)
+	return unused, nil
+}
+
+func (g *ResourceGenerator) hooks() *Hooks {
+	if g.ResourceConfig.Generate.CRUDAutoOptions == nil {
+		return nil
+	}
+	return g.ResourceConfig.Generate.CRUDAutoOptions.Hooks
+}
+
+// HookMethods returns the names of the hook methods enabled in the configuration
+func (g *ResourceGenerator) HookMethods() []string {
+	return g.hooks().EnabledMethods()
+}
@@ -0,0 +1,57 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHookMethods(t *testing.T) {
+	existing := []byte(`package widget
+
+func (r *Widget) BeforeCreate() {}
+
+func (r *Widget) AfterDelete() {}
+
+func (r *Widget) BeforeUpdate(ctx, req, resp, m, manifest any) {}
+
+func (r *Widget) helper() {}
+
+func (r *Other) AfterRead() {}
+`)
+	gen := ResourceGenerator{ResourceConfig: testResourceConfig(t)}
+
+	missing, err := gen.MissingHookMethods(existing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"AfterRead"}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Fatalf("expected %q got %q", expected, missing)
+	}
+
+	unused, err := gen.UnusedHookMethods(existing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []string{"AfterDelete"}
+	if !reflect.DeepEqual(unused, expected) {
+		t.Fatalf("expected %q got %q", expected, unused)
+	}
+}
+
+func TestHooksIsEmpty(t *testing.T) {
+	hooks := &Hooks{
+		BeforeHook: &BeforeHook{},
+		AfterHook:  &AfterHook{Update: true},
+	}
+	if hooks.IsEmpty() {
+		t.Fatalf("expected hooks with an after update hook not to be empty")
+	}
+	var nilHooks *Hooks
+	if !nilHooks.IsEmpty() {
+		t.Fatalf("expected nil hooks to be empty")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sync"
+)
+
+// Version is the version of the generator, it can be set at build time with
+// -ldflags "-X github.com/hashicorp/terraform-plugin-codegen-kubernetes/internal/generator.Version=..."
+var Version = ""
+
+// generatorVersion returns Version or the version and VCS revision of the
+// build. Builds from a modified tree or without VCS information, such as
+// with go run, use a hash of the executable so that any change to the
+// generator code is seen.
+func generatorVersion() (string, error) {
+	if Version != "" {
+		return Version, nil
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		revision, modified := "", false
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				revision = s.Value
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+		if revision != "" && !modified {
+			return info.Main.Version + " vcs.revision=" + revision, nil
+		}
+	}
+	return executableHash()
+}
+
+// executableHash hashes the running executable, it is only read once
+var executableHash = sync.OnceValues(func() (string, error) {
+	filename, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("error finding the generator executable: %v", err)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("error reading %s: %v", filename, err)
+	}
+	return "executable " + hex.EncodeToString(hash.Sum(nil)), nil
+})
+
+// InputsHasher hashes the inputs used to generate the code for a resource
+// so that resources whose inputs have not changed can be skipped
+type InputsHasher struct {
+	files map[string]string
+}
+
+// NewInputsHasher returns an InputsHasher
+func NewInputsHasher() *InputsHasher {
+	return &InputsHasher{files: map[string]string{}}
+}
+
+// ResourceHash returns a hash of the resource configuration, the provider
+// configuration, the OpenAPI specs and snapshots the resource is generated
+// from, the templates and the generator version
+func (h *InputsHasher) ResourceHash(provider ProviderConfig, r ResourceConfig) (string, error) {
+	version, err := generatorVersion()
+	if err != nil {
+		return "", fmt.Errorf("error finding the generator version: %v", err)
+	}
+	hash := sha256.New()
+	fmt.Fprintf(hash, "version %q\n", version)
+	for _, t := range templates {
+		fmt.Fprintf(hash, "template %x\n", sha256.Sum256([]byte(t)))
+	}
+
+	config, err := json.Marshal(struct {
+		Provider ProviderConfig
+		Resource ResourceConfig
+	}{provider, r})
+	if err != nil {
+		return "", fmt.Errorf("error marshalling configuration: %v", err)
+	}
+	fmt.Fprintf(hash, "config %x\n", sha256.Sum256(config))
+
+	files := []string{r.OpenAPIConfig.Filename}
+	for _, u := range r.StateUpgrades {
+		if u.Snapshot != "" {
+			files = append(files, u.Snapshot)
+		}
+		if u.OpenAPIConfig != nil {
+			files = append(files, u.OpenAPIConfig.Filename)
+		}
+	}
+	for _, f := range files {
+		fileHash, err := h.fileHash(f)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "file %q %s\n", f, fileHash)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (h *InputsHasher) fileHash(filename string) (string, error) {
+	if fileHash, ok := h.files[filename]; ok {
+		return fileHash, nil
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("error reading %s: %v", filename, err)
+	}
+	h.files[filename] = hex.EncodeToString(hash.Sum(nil))
+	return h.files[filename], nil
+}
@@ -0,0 +1,63 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResourceHash(t *testing.T) {
+	cfg := ResourceConfig{
+		Name: "kubernetes_widget_v1",
+		OpenAPIConfig: TerraformPluginGenOpenAPIConfig{
+			Filename: "testdata/widget_framework_ir.json",
+		},
+	}
+	provider := DefaultProviderConfig()
+
+	h := NewInputsHasher()
+	hash, err := h.ResourceHash(provider, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := NewInputsHasher().ResourceHash(provider, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash != again {
+		t.Fatalf("expected %q got %q", hash, again)
+	}
+
+	cfg.Description = "changed"
+	changed, err := h.ResourceHash(provider, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash == changed {
+		t.Fatalf("expected hash to change when the configuration changes")
+	}
+
+	cfg.OpenAPIConfig.Filename = "testdata/missing.json"
+	if _, err := h.ResourceHash(provider, cfg); err == nil {
+		t.Fatalf("expected error for missing OpenAPI spec")
+	}
+}
+
+func TestGeneratorVersion(t *testing.T) {
+	// test binaries are built without VCS information
+	version, err := generatorVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(version, "executable ") {
+		t.Errorf("expected the executable to be hashed got %q", version)
+	}
+
+	defer func(v string) { Version = v }(Version)
+	Version = "v1.2.3"
+	if version, _ := generatorVersion(); version != "v1.2.3" {
+		t.Errorf("expected %q got %q", "v1.2.3", version)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// mergeNewMethods appends the functions and methods in generated that are not
+// declared in existing to the end of existing, adding any imports they need.
+// The declarations already in existing are left untouched. It returns the
+// merged source and the names of the functions that were added.
+func mergeNewMethods(existing, generated []byte) ([]byte, []string, error) {
+	fset := token.NewFileSet()
+	existingFile, err := parser.ParseFile(fset, "existing.go", existing, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	generatedFile, err := parser.ParseFile(fset, "generated.go", generated, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	declared := map[string]bool{}
+	for _, decl := range existingFile.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			declared[funcDeclName(fn)] = true
+		}
+	}
+
+	added := []string{}
+	referenced := map[string]bool{}
+	var methods bytes.Buffer
+	for _, decl := range generatedFile.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || declared[funcDeclName(fn)] {
+			continue
+		}
+		for name := range referencedPackages(fn) {
+			referenced[name] = true
+		}
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		methods.WriteString("\n")
+		methods.Write(generated[fset.Position(start).Offset:fset.Position(fn.End()).Offset])
+		methods.WriteString("\n")
+		added = append(added, funcDeclName(fn))
+	}
+	if len(added) == 0 {
+		return existing, nil, nil
+	}
+
+	// missing imports that the added functions use are added in a separate
+	// import declaration after the package clause, this keeps the existing
+	// declarations as they are
+	imported := map[string]bool{}
+	for _, spec := range existingFile.Imports {
+		imported[spec.Path.Value] = true
+	}
+	var imports bytes.Buffer
+	for _, spec := range generatedFile.Imports {
+		if imported[spec.Path.Value] {
+			continue
+		}
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !referenced[importName(spec, path)] {
+			continue
+		}
+		if spec.Name != nil {
+			fmt.Fprintf(&imports, "\nimport %s %q\n", spec.Name.Name, path)
+		} else {
+			fmt.Fprintf(&imports, "\nimport %q\n", path)
+		}
+	}
+
+	var merged bytes.Buffer
+	packageEnd := fset.Position(existingFile.Name.End()).Offset
+	merged.Write(existing[:packageEnd])
+	merged.Write(imports.Bytes())
+	merged.Write(existing[packageEnd:])
+	merged.Write(methods.Bytes())
+
+	src, err := format.Source(merged.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, added, nil
+}
+
+// funcDeclName returns the name of a function, prefixed by the receiver type for methods
+func funcDeclName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// referencedPackages returns the names of the packages used in a function,
+// these are the identifiers of selectors that are not declared in the file
+func referencedPackages(fn *ast.FuncDecl) map[string]bool {
+	names := map[string]bool{}
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+				names[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// importName returns the name an import is referred to by, which is the
+// last element of the path that is not a major version suffix
+func importName(spec *ast.ImportSpec, importPath string) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	name := path.Base(importPath)
+	if v := strings.TrimPrefix(name, "v"); v != name && v != "" && strings.Trim(v, "0123456789") == "" {
+		name = path.Base(path.Dir(importPath))
+	}
+	return name
+}
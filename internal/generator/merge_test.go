@@ -0,0 +1,98 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeNewMethods(t *testing.T) {
+	existing := `package widget
+
+import "context"
+
+// BeforeCreate is implemented by the user
+func (r *Widget) BeforeCreate(ctx context.Context) {
+	doSomething()
+}
+`
+	generated := `package widget
+
+import (
+	"context"
+	"fmt"
+)
+
+func (r *Widget) BeforeCreate(ctx context.Context) {
+	// TODO: Add BeforeCreate logic
+}
+
+// AfterRead is called after the resource is read
+func (r *Widget) AfterRead(ctx context.Context) {
+	fmt.Println("TODO")
+}
+`
+	merged, added, err := mergeNewMethods([]byte(existing), []byte(generated))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 1 || added[0] != "Widget.AfterRead" {
+		t.Fatalf("expected %q got %q", []string{"Widget.AfterRead"}, added)
+	}
+	for _, s := range []string{"doSomething()", "// AfterRead is called after the resource is read", `import "fmt"`} {
+		if !strings.Contains(string(merged), s) {
+			t.Fatalf("expected merged source to contain %q got:\n%s", s, merged)
+		}
+	}
+	if strings.Contains(string(merged), "TODO: Add BeforeCreate logic") {
+		t.Fatalf("expected existing BeforeCreate to be kept got:\n%s", merged)
+	}
+
+	again, added, err := mergeNewMethods(merged, []byte(generated))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 0 || string(again) != string(merged) {
+		t.Fatalf("expected merging twice to make no changes got:\n%s", again)
+	}
+}
+
+func TestMergeNewMethodsUnusedImports(t *testing.T) {
+	existing := `package widget
+
+func (r *Widget) BeforeCreate() {
+	doSomething()
+}
+`
+	generated := `package widget
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func (r *Widget) BeforeCreate() {
+	fmt.Println("TODO")
+}
+
+func (r *Widget) AfterRead(ctx context.Context, id types.String) {
+	_ = id
+}
+`
+	merged, _, err := mergeNewMethods([]byte(existing), []byte(generated))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{`import "context"`, `import "github.com/hashicorp/terraform-plugin-framework/types"`} {
+		if !strings.Contains(string(merged), s) {
+			t.Fatalf("expected merged source to contain %q got:\n%s", s, merged)
+		}
+	}
+	if strings.Contains(string(merged), `"fmt"`) {
+		t.Fatalf("expected fmt not to be imported as only the existing method uses it got:\n%s", merged)
+	}
+}
@@ -0,0 +1,412 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-codegen-spec/code"
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+	specschema "github.com/hashicorp/terraform-plugin-codegen-spec/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// maxRefDepth limits how many references are followed when resolving a schema
+const maxRefDepth = 10
+
+const rfc3339Pattern = `^\d{4}-\d{2}-\d{2}[Tt]\d{2}:\d{2}:\d{2}(\.\d+)?([Zz]|[+-]\d{2}:\d{2})$`
+
+// OpenAPIDocument is the subset of an OpenAPI document the generator reads
+// validation constraints from
+type OpenAPIDocument struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema *openAPISchema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+// openAPISchema is the subset of an OpenAPI schema object that describes
+// the value of an attribute
+type openAPISchema struct {
+	Ref              string                    `json:"$ref"`
+	AllOf            []*openAPISchema          `json:"allOf"`
+	Type             string                    `json:"type"`
+	Format           string                    `json:"format"`
+	Enum             []any                     `json:"enum"`
+	Pattern          string                    `json:"pattern"`
+	Minimum          *float64                  `json:"minimum"`
+	Maximum          *float64                  `json:"maximum"`
+	ExclusiveMinimum json.RawMessage           `json:"exclusiveMinimum"`
+	ExclusiveMaximum json.RawMessage           `json:"exclusiveMaximum"`
+	MinLength        *int64                    `json:"minLength"`
+	MaxLength        *int64                    `json:"maxLength"`
+	MinItems         *int64                    `json:"minItems"`
+	MaxItems         *int64                    `json:"maxItems"`
+	MinProperties    *int64                    `json:"minProperties"`
+	MaxProperties    *int64                    `json:"maxProperties"`
+	Properties       map[string]*openAPISchema `json:"properties"`
+	Items            *openAPISchema            `json:"items"`
+	ValidationRules  []openAPIValidationRule   `json:"x-kubernetes-validations"`
+}
+
+// openAPIValidationRule is a CEL rule from the x-kubernetes-validations extension
+type openAPIValidationRule struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func readOpenAPIDocument(filename string) (*OpenAPIDocument, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	doc := &OpenAPIDocument{}
+	if err := yaml.Unmarshal(contents, doc); err != nil {
+		return nil, fmt.Errorf("error parsing OpenAPI spec %q: %v", filename, err)
+	}
+	return doc, nil
+}
+
+// requestSchema returns the schema of the request body for the POST operation on the path
+func (d *OpenAPIDocument) requestSchema(p string) *openAPISchema {
+	if d == nil {
+		return nil
+	}
+	raw, ok := d.Paths[p]["post"]
+	if !ok {
+		return nil
+	}
+	var op openAPIOperation
+	if err := json.Unmarshal(raw, &op); err != nil || op.RequestBody == nil {
+		return nil
+	}
+	if c, ok := op.RequestBody.Content["application/json"]; ok {
+		return d.resolve(c.Schema)
+	}
+	for _, c := range op.RequestBody.Content {
+		return d.resolve(c.Schema)
+	}
+	return nil
+}
+
+// resolve follows references, and allOf with a single schema which
+// Kubernetes uses to add a description to a reference
+func (d *OpenAPIDocument) resolve(s *openAPISchema) *openAPISchema {
+	for i := 0; s != nil && i < maxRefDepth; i++ {
+		switch {
+		case s.Ref != "":
+			s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		case len(s.AllOf) == 1 && s.Type == "" && s.Properties == nil:
+			s = s.AllOf[0]
+		default:
+			return s
+		}
+	}
+	return s
+}
+
+// property returns the property for the attribute, tfplugingen-openapi
+// converts the property names to snake case, e.g. hostIP is host_ip
+func (s *openAPISchema) property(attributeName string) *openAPISchema {
+	name := strings.ReplaceAll(attributeName, "_", "")
+	for k, v := range s.Properties {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// addOpenAPIValidators adds validators for the constraints in the OpenAPI
+// spec, such as enum, pattern and minimum, to the attributes of the resource
+func addOpenAPIValidators(doc *OpenAPIDocument, r ResourceConfig, spec *specresource.Resource) {
+	s := doc.requestSchema(r.OpenAPIConfig.CreatePath)
+	if s == nil {
+		slog.Warn("No request body schema found in OpenAPI spec, skipping validators", "resource", r.Name, "path", r.OpenAPIConfig.CreatePath)
+		return
+	}
+	addAttributeValidators(doc, spec.Schema.Attributes, s, "")
+}
+
+func addAttributeValidators(doc *OpenAPIDocument, attrs specresource.Attributes, s *openAPISchema, attributePath string) {
+	walkOpenAPIAttributes(doc, attrs, s, attributePath, func(attr *specresource.Attribute, prop *openAPISchema, attributePath string) {
+		for _, v := range openAPIValidators(*attr, prop, attributePath) {
+			addSpecValidator(attr, v)
+		}
+	})
+}
+
+// walkOpenAPIAttributes calls fn with every attribute in the spec that has
+// a matching property in the OpenAPI schema
+func walkOpenAPIAttributes(doc *OpenAPIDocument, attrs specresource.Attributes, s *openAPISchema, attributePath string, fn func(*specresource.Attribute, *openAPISchema, string)) {
+	if s == nil {
+		return
+	}
+	for i := range attrs {
+		attr := &attrs[i]
+		prop := doc.resolve(s.property(attr.Name))
+		if prop == nil {
+			continue
+		}
+		fn(attr, prop, attributePath+attr.Name)
+		switch {
+		case attr.SingleNested != nil:
+			walkOpenAPIAttributes(doc, attr.SingleNested.Attributes, prop, attributePath+attr.Name+".", fn)
+		case attr.ListNested != nil:
+			walkOpenAPIAttributes(doc, attr.ListNested.NestedObject.Attributes, doc.resolve(prop.Items), attributePath+attr.Name+"[*].", fn)
+		case attr.SetNested != nil:
+			walkOpenAPIAttributes(doc, attr.SetNested.NestedObject.Attributes, doc.resolve(prop.Items), attributePath+attr.Name+"[*].", fn)
+		}
+	}
+}
+
+// openAPIValidator is a validator for a constraint in the schema, with
+// values that pass and fail it as Go expressions for the generated tests
+type openAPIValidator struct {
+	validator specschema.CustomValidator
+	valid     []string
+	invalid   []string
+}
+
+// openAPIValidators returns the validators for the constraints in the schema
+func openAPIValidators(attr specresource.Attribute, s *openAPISchema, attributePath string) []specschema.CustomValidator {
+	validators := []specschema.CustomValidator{}
+	for _, v := range constraintValidators(attr, s, attributePath) {
+		validators = append(validators, v.validator)
+	}
+	return validators
+}
+
+// constraintValidators returns the validators for the constraints in the
+// schema, examples are only given for string and int64 attributes
+func constraintValidators(attr specresource.Attribute, s *openAPISchema, attributePath string) []openAPIValidator {
+	validators := []openAPIValidator{}
+	switch {
+	case attr.String != nil:
+		if values := enumValues(s.Enum, func(v any) (string, bool) {
+			str, ok := v.(string)
+			return fmt.Sprintf("%q", str), ok
+		}); values != "" {
+			validators = append(validators, openAPIValidator{
+				validator: frameworkValidator("stringvalidator", "OneOf(%s)", values),
+				valid:     []string{fmt.Sprintf("%q", s.Enum[0])},
+				invalid:   []string{fmt.Sprintf("%q", stringNotInEnum(s.Enum))},
+			})
+		}
+		if v, ok := sizeValidator("stringvalidator", "Length", s.MinLength, s.MaxLength); ok {
+			valid, invalid := lengthExamples(s.MinLength, s.MaxLength)
+			validators = append(validators, openAPIValidator{v, valid, invalid})
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err != nil {
+				slog.Warn("Skipping pattern that is not a valid Go regular expression", "path", attributePath, "pattern", s.Pattern, "err", err)
+			} else {
+				valid, invalid := patternExamples(re, s.Enum)
+				validators = append(validators, openAPIValidator{regexValidator(s.Pattern, ""), valid, invalid})
+			}
+		}
+		if s.Format == "date-time" {
+			validators = append(validators, openAPIValidator{
+				validator: regexValidator(rfc3339Pattern, "must be an RFC 3339 date-time"),
+				valid:     []string{`"2024-01-01T00:00:00Z"`},
+				invalid:   []string{`"2024-01-01"`},
+			})
+		}
+	case attr.Int64 != nil:
+		if values := enumValues(s.Enum, func(v any) (string, bool) {
+			n, ok := v.(float64)
+			return fmt.Sprintf("%d", int64(n)), ok
+		}); values != "" {
+			validators = append(validators, openAPIValidator{
+				validator: frameworkValidator("int64validator", "OneOf(%s)", values),
+				valid:     []string{fmt.Sprintf("%d", int64(s.Enum[0].(float64)))},
+				invalid:   []string{fmt.Sprintf("%d", int64NotInEnum(s.Enum))},
+			})
+		}
+		if lo, hi := int64Range(s); lo != nil || hi != nil {
+			valid, invalid := rangeExamples(lo, hi)
+			validators = append(validators, openAPIValidator{int64RangeValidator(lo, hi), valid, invalid})
+		}
+	case attr.List != nil || attr.ListNested != nil:
+		if v, ok := sizeValidator("listvalidator", "Size", s.MinItems, s.MaxItems); ok {
+			validators = append(validators, openAPIValidator{validator: v})
+		}
+	case attr.Set != nil || attr.SetNested != nil:
+		if v, ok := sizeValidator("setvalidator", "Size", s.MinItems, s.MaxItems); ok {
+			validators = append(validators, openAPIValidator{validator: v})
+		}
+	case attr.Map != nil:
+		if v, ok := sizeValidator("mapvalidator", "Size", s.MinProperties, s.MaxProperties); ok {
+			validators = append(validators, openAPIValidator{validator: v})
+		}
+	}
+	return validators
+}
+
+// enumValues returns the enum values formatted as Go literals, or an empty
+// string if any of the values is not of the expected type
+func enumValues(enum []any, format func(any) (string, bool)) string {
+	values := []string{}
+	for _, v := range enum {
+		s, ok := format(v)
+		if !ok {
+			return ""
+		}
+		values = append(values, s)
+	}
+	return strings.Join(values, ", ")
+}
+
+// int64Range returns the bounds of an integer, taking the int32 format and
+// exclusive bounds into account
+func int64Range(s *openAPISchema) (lo, hi *int64) {
+	if s.Format == "int32" {
+		lo, hi = ptr(int64(math.MinInt32)), ptr(int64(math.MaxInt32))
+	}
+	if s.Minimum != nil {
+		v := int64(*s.Minimum)
+		if string(s.ExclusiveMinimum) == "true" {
+			v++
+		}
+		if lo == nil || v > *lo {
+			lo = &v
+		}
+	}
+	if s.Maximum != nil {
+		v := int64(*s.Maximum)
+		if string(s.ExclusiveMaximum) == "true" {
+			v--
+		}
+		if hi == nil || v < *hi {
+			hi = &v
+		}
+	}
+	return lo, hi
+}
+
+func int64RangeValidator(lo, hi *int64) specschema.CustomValidator {
+	switch {
+	case lo != nil && hi != nil:
+		return frameworkValidator("int64validator", "Between(%d, %d)", *lo, *hi)
+	case lo != nil:
+		return frameworkValidator("int64validator", "AtLeast(%d)", *lo)
+	}
+	return frameworkValidator("int64validator", "AtMost(%d)", *hi)
+}
+
+// sizeValidator returns a validator using the Between, AtLeast and AtMost
+// functions of pkg, e.g. listvalidator.SizeBetween
+func sizeValidator(pkg, prefix string, lo, hi *int64) (specschema.CustomValidator, bool) {
+	switch {
+	case lo != nil && hi != nil:
+		return frameworkValidator(pkg, prefix+"Between(%d, %d)", *lo, *hi), true
+	case lo != nil:
+		return frameworkValidator(pkg, prefix+"AtLeast(%d)", *lo), true
+	case hi != nil:
+		return frameworkValidator(pkg, prefix+"AtMost(%d)", *hi), true
+	}
+	return specschema.CustomValidator{}, false
+}
+
+func regexValidator(pattern, message string) specschema.CustomValidator {
+	v := frameworkValidator("stringvalidator", "RegexMatches(regexp.MustCompile(%q), %q)", pattern, message)
+	v.Imports = append(v.Imports, code.Import{Path: "regexp"})
+	return v
+}
+
+func frameworkValidator(pkg, format string, args ...any) specschema.CustomValidator {
+	return specschema.CustomValidator{
+		Imports:          []code.Import{{Path: path.Join(frameworkValidatorsImportPath, pkg)}},
+		SchemaDefinition: pkg + "." + fmt.Sprintf(format, args...),
+	}
+}
+
+// addSpecValidator adds a custom validator to the attribute in the framework IR
+func addSpecValidator(attr *specresource.Attribute, v specschema.CustomValidator) {
+	switch {
+	case attr.Bool != nil:
+		attr.Bool.Validators = append(attr.Bool.Validators, specschema.BoolValidator{Custom: &v})
+	case attr.String != nil:
+		attr.String.Validators = append(attr.String.Validators, specschema.StringValidator{Custom: &v})
+	case attr.Number != nil:
+		attr.Number.Validators = append(attr.Number.Validators, specschema.NumberValidator{Custom: &v})
+	case attr.Int64 != nil:
+		attr.Int64.Validators = append(attr.Int64.Validators, specschema.Int64Validator{Custom: &v})
+	case attr.Map != nil:
+		attr.Map.Validators = append(attr.Map.Validators, specschema.MapValidator{Custom: &v})
+	case attr.List != nil:
+		attr.List.Validators = append(attr.List.Validators, specschema.ListValidator{Custom: &v})
+	case attr.Set != nil:
+		attr.Set.Validators = append(attr.Set.Validators, specschema.SetValidator{Custom: &v})
+	case attr.SingleNested != nil:
+		attr.SingleNested.Validators = append(attr.SingleNested.Validators, specschema.ObjectValidator{Custom: &v})
+	case attr.ListNested != nil:
+		attr.ListNested.Validators = append(attr.ListNested.Validators, specschema.ListValidator{Custom: &v})
+	case attr.SetNested != nil:
+		attr.SetNested.Validators = append(attr.SetNested.Validators, specschema.SetValidator{Custom: &v})
+	}
+}
+
+// specValidators returns the custom validators for the attribute in the framework IR
+func specValidators(attr specresource.Attribute) []*specschema.CustomValidator {
+	validators := []*specschema.CustomValidator{}
+	switch {
+	case attr.Bool != nil:
+		for _, v := range attr.Bool.Validators {
+			validators = append(validators, v.Custom)
+		}
+	case attr.String != nil:
+		for _, v := range attr.String.Validators {
+			validators = append(validators, v.Custom)
+		}
+	case attr.Number != nil:
+		for _, v := range attr.Number.Validators {
+			validators = append(validators, v.Custom)
+		}
+	case attr.Int64 != nil:
+		for _, v := range attr.Int64.Validators {
+			validators = append(validators, v.Custom)
+		}
+	case attr.Map != nil:
+		for _, v := range attr.Map.Validators {
+			validators = append(validators, v.Custom)
+		}
+	case attr.List != nil:
+		for _, v := range attr.List.Validators {
+			validators = append(validators, v.Custom)
+		}
+	case attr.Set != nil:
+		for _, v := range attr.Set.Validators {
+			validators = append(validators, v.Custom)
+		}
+	case attr.SingleNested != nil:
+		for _, v := range attr.SingleNested.Validators {
+			validators = append(validators, v.Custom)
+		}
+	case attr.ListNested != nil:
+		for _, v := range attr.ListNested.Validators {
+			validators = append(validators, v.Custom)
+		}
+	case attr.SetNested != nil:
+		for _, v := range attr.SetNested.Validators {
+			validators = append(validators, v.Custom)
+		}
+	}
+	return validators
+}
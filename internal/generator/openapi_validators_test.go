@@ -0,0 +1,49 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIValidators(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := readOpenAPIDocument("testdata/widget_openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testResourceConfig(t)
+	cfg.OpenAPIConfig.CreatePath = "/apis/example.com/v1/namespaces/{namespace}/widgets"
+	addOpenAPIValidators(doc, cfg, &spec)
+
+	gen := NewResourceGenerator(DefaultProviderConfig(), cfg, spec)
+	code, err := gen.GenerateSchemaFunctionCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`mapvalidator.SizeAtMost(16),`,
+		`stringvalidator.LengthAtMost(253),`,
+		`int64validator.Between(0, 2147483647),`,
+		`stringvalidator.OneOf("Always", "OnFailure", "Never"),`,
+		`listvalidator.SizeAtLeast(1),`,
+		`listvalidator.SizeAtMost(8),`,
+		`stringvalidator.LengthBetween(1, 15),`,
+		"stringvalidator.RegexMatches(regexp.MustCompile(\"^[a-z]([-a-z0-9]*[a-z0-9])?$\"), \"\"),",
+		`int64validator.Between(1, 65535),`,
+		`"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"`,
+		`"regexp"`,
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("expected schema to contain %q", expected)
+		}
+	}
+	if strings.Contains(code, "numbervalidator") {
+		t.Errorf("expected no validators for number attributes")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// SchemaSnapshot is a snapshot of a generated schema that is stored next to
+// the generated code so that breaking changes can be detected between runs
+type SchemaSnapshot struct {
+	Name    string `json:"name"`
+	Version int64  `json:"version"`
+
+	// Attributes is a map of attribute paths to their snapshot, the paths use
+	// the same syntax as the generator configuration, e.g. spec.ports[*].name
+	Attributes map[string]AttributeSnapshot `json:"attributes"`
+}
+
+// AttributeSnapshot contains the parts of an attribute that are
+// relevant for detecting breaking changes
+type AttributeSnapshot struct {
+	AttributeType string `json:"attribute_type"`
+	ElementType   string `json:"element_type,omitempty"`
+	Required      bool   `json:"required,omitempty"`
+	Computed      bool   `json:"computed,omitempty"`
+}
+
+// SchemaChange describes a difference between two schema snapshots
+type SchemaChange struct {
+	Path        string
+	Description string
+	Breaking    bool
+}
+
+// NewSchemaSnapshot creates a snapshot of the supplied schema
+func NewSchemaSnapshot(s SchemaGenerator) SchemaSnapshot {
+	snapshot := SchemaSnapshot{
+		Name:       s.Name,
+		Version:    s.Version,
+		Attributes: map[string]AttributeSnapshot{},
+	}
+	snapshotAttributes(snapshot.Attributes, s.Attributes, "")
+	return snapshot
+}
+
+func snapshotAttributes(snapshot map[string]AttributeSnapshot, attrs AttributesGenerator, path string) {
+	for _, a := range attrs {
+		attributePath := path + a.Name
+		snapshot[attributePath] = AttributeSnapshot{
+			AttributeType: a.AttributeType,
+			ElementType:   a.ElementType,
+			Required:      a.Required,
+			Computed:      a.Computed,
+		}
+		switch a.AttributeType {
+		case SingleNestedAttributeType:
+			snapshotAttributes(snapshot, a.NestedAttributes, attributePath+".")
+		case ListNestedAttributeType, SetNestedAttributeType:
+			snapshotAttributes(snapshot, a.NestedAttributes, attributePath+"[*].")
+		}
+	}
+}
+
+// ReadSchemaSnapshot reads a schema snapshot from a JSON file
+func ReadSchemaSnapshot(filename string) (SchemaSnapshot, error) {
+	snapshot := SchemaSnapshot{}
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return snapshot, err
+	}
+	err = json.Unmarshal(contents, &snapshot)
+	if err != nil {
+		return snapshot, fmt.Errorf("error parsing schema snapshot %q: %v", filename, err)
+	}
+	return snapshot, nil
+}
+
+// MarshalSchemaSnapshot returns the JSON encoding of the schema snapshot
+func MarshalSchemaSnapshot(snapshot SchemaSnapshot) ([]byte, error) {
+	contents, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(contents, '\n'), nil
+}
+
+// DiffSchemaSnapshots compares a previous schema snapshot to the current one
+// and returns the changes sorted by attribute path
+func DiffSchemaSnapshots(previous, current SchemaSnapshot) []SchemaChange {
+	changes := []SchemaChange{}
+	for path, prev := range previous.Attributes {
+		curr, ok := current.Attributes[path]
+		if !ok {
+			changes = append(changes, SchemaChange{path, "attribute was removed", true})
+			continue
+		}
+		if prev.AttributeType != curr.AttributeType || prev.ElementType != curr.ElementType {
+			changes = append(changes, SchemaChange{
+				path,
+				fmt.Sprintf("type changed from %s to %s", describeType(prev), describeType(curr)),
+				true,
+			})
+		}
+		if !prev.Required && curr.Required {
+			changes = append(changes, SchemaChange{path, "attribute is now required", true})
+		}
+		if prev.Required && !curr.Required {
+			changes = append(changes, SchemaChange{path, "attribute is no longer required", false})
+		}
+		if prev.Computed != curr.Computed {
+			changes = append(changes, SchemaChange{path, fmt.Sprintf("computed changed from %t to %t", prev.Computed, curr.Computed), false})
+		}
+	}
+	for path, curr := range current.Attributes {
+		if _, ok := previous.Attributes[path]; ok {
+			continue
+		}
+		if curr.Required {
+			changes = append(changes, SchemaChange{path, "required attribute was added", true})
+		} else {
+			changes = append(changes, SchemaChange{path, "attribute was added", false})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func describeType(a AttributeSnapshot) string {
+	if a.ElementType != "" {
+		return fmt.Sprintf("%s(%s)", a.AttributeType, a.ElementType)
+	}
+	return a.AttributeType
+}
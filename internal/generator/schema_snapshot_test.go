@@ -0,0 +1,67 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSchemaSnapshot(t *testing.T) {
+	schema := SchemaGenerator{
+		Name: "kubernetes_test_v1",
+		Attributes: AttributesGenerator{
+			{Name: "id", AttributeType: StringAttributeType, Computed: true},
+			{Name: "spec", AttributeType: SingleNestedAttributeType, NestedAttributes: AttributesGenerator{
+				{Name: "args", AttributeType: ListAttributeType, ElementType: StringElementType},
+				{Name: "ports", AttributeType: ListNestedAttributeType, NestedAttributes: AttributesGenerator{
+					{Name: "port", AttributeType: Int64AttributeType, Required: true},
+				}},
+			}},
+		},
+	}
+
+	expected := map[string]AttributeSnapshot{
+		"id":                 {AttributeType: StringAttributeType, Computed: true},
+		"spec":               {AttributeType: SingleNestedAttributeType},
+		"spec.args":          {AttributeType: ListAttributeType, ElementType: StringElementType},
+		"spec.ports":         {AttributeType: ListNestedAttributeType},
+		"spec.ports[*].port": {AttributeType: Int64AttributeType, Required: true},
+	}
+
+	actual := NewSchemaSnapshot(schema)
+	if !reflect.DeepEqual(expected, actual.Attributes) {
+		t.Fatalf("expected %v got %v", expected, actual.Attributes)
+	}
+}
+
+func TestDiffSchemaSnapshots(t *testing.T) {
+	previous := SchemaSnapshot{Attributes: map[string]AttributeSnapshot{
+		"removed":      {AttributeType: StringAttributeType},
+		"retyped":      {AttributeType: ListAttributeType, ElementType: StringElementType},
+		"now_required": {AttributeType: StringAttributeType},
+		"now_optional": {AttributeType: StringAttributeType, Required: true},
+		"unchanged":    {AttributeType: BoolAttributeType},
+	}}
+	current := SchemaSnapshot{Attributes: map[string]AttributeSnapshot{
+		"retyped":      {AttributeType: ListAttributeType, ElementType: Int64ElementType},
+		"now_required": {AttributeType: StringAttributeType, Required: true},
+		"now_optional": {AttributeType: StringAttributeType},
+		"unchanged":    {AttributeType: BoolAttributeType},
+		"added":        {AttributeType: StringAttributeType},
+	}}
+
+	expected := []SchemaChange{
+		{"added", "attribute was added", false},
+		{"now_optional", "attribute is no longer required", false},
+		{"now_required", "attribute is now required", true},
+		{"removed", "attribute was removed", true},
+		{"retyped", "type changed from ListAttribute(StringType) to ListAttribute(Int64Type)", true},
+	}
+
+	actual := DiffSchemaSnapshots(previous, current)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected %v got %v", expected, actual)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"log/slog"
+	"sync"
+
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+)
+
+// ResourceSpecCache generates the framework IR for all of the resources that
+// use the same OpenAPI spec in a single run of tfplugingen-openapi, so that
+// each spec is only parsed once. It is safe for concurrent use.
+type ResourceSpecCache struct {
+	mu        sync.Mutex
+	resources map[string][]ResourceConfig
+	entries   map[string]*resourceSpecCacheEntry
+}
+
+type resourceSpecCacheEntry struct {
+	once  sync.Once
+	doc   *OpenAPIDocument
+	specs map[string]specresource.Resource
+	err   error
+}
+
+// NewResourceSpecCache returns a cache for the supplied resources
+func NewResourceSpecCache(resources []ResourceConfig) *ResourceSpecCache {
+	c := &ResourceSpecCache{
+		resources: map[string][]ResourceConfig{},
+		entries:   map[string]*resourceSpecCacheEntry{},
+	}
+	for _, r := range resources {
+		filename := r.OpenAPIConfig.Filename
+		c.resources[filename] = append(c.resources[filename], r)
+	}
+	return c
+}
+
+// ResourceSpec returns the spec.Resource for the resource, generating the
+// framework IR for its OpenAPI spec the first time the spec is used
+func (c *ResourceSpecCache) ResourceSpec(r ResourceConfig) (specresource.Resource, error) {
+	filename := r.OpenAPIConfig.Filename
+
+	entry := c.entry(filename)
+	if spec, ok := entry.specs[r.Name]; ok {
+		return spec, nil
+	}
+
+	// fall back to generating the resource on its own so that one bad
+	// resource configuration does not fail every resource using the spec
+	if entry.err != nil {
+		slog.Warn("Error generating framework IR for OpenAPI spec, retrying for resource", "filename", filename, "resource", r.Name, "err", entry.err)
+	}
+	specs, err := generateResourceSpecs(filename, entry.doc, []ResourceConfig{r})
+	if err != nil {
+		return specresource.Resource{}, err
+	}
+	return specs[r.Name], nil
+}
+
+// OpenAPIDocument returns the OpenAPI spec of the resource, which is read
+// once with the framework IR, or nil if it could not be read
+func (c *ResourceSpecCache) OpenAPIDocument(r ResourceConfig) *OpenAPIDocument {
+	return c.entry(r.OpenAPIConfig.Filename).doc
+}
+
+// entry returns the cache entry for the OpenAPI spec, reading the spec and
+// generating the framework IR for its resources the first time it is used
+func (c *ResourceSpecCache) entry(filename string) *resourceSpecCacheEntry {
+	c.mu.Lock()
+	entry, ok := c.entries[filename]
+	if !ok {
+		entry = &resourceSpecCacheEntry{}
+		c.entries[filename] = entry
+	}
+	resources := c.resources[filename]
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.doc = readValidatorsOpenAPIDocument(filename)
+		entry.specs, entry.err = generateResourceSpecs(filename, entry.doc, resources)
+	})
+	return entry
+}
@@ -18,25 +18,42 @@ Computed: true,
 Sensitive: true,
 {{- end }}
 
-{{- if .Immutable }}
+{{- if .DeprecationMessage }}
+DeprecationMessage: {{ printf "%q" .DeprecationMessage }},
+{{- end }}
+
+{{- if .Default }}
+Default: {{ .Default }},
+{{- end }}
+
+{{- if or .Immutable .PlanModifiers }}
 PlanModifiers: []planmodifier.{{ .PlanModifierType }}{
-  {{ .PlanModifierPackage }}.RequiresReplace(), 
+  {{- if .Immutable }}
+  {{ .PlanModifierPackage }}.RequiresReplace(),
+  {{- end }}
+  {{- range .PlanModifiers }}
+  {{ . }},
+  {{- end }}
 },
 {{- end }}
 
-{{/* TODO don't share PlanModifierType */}}
-{{- if ne .GenAIValidatorType "" }}
-Validators: []validator.{{ .PlanModifierType }}{
-  {{ .GenAIValidatorType }}{}, 
+{{- if or .GenAIValidatorType .Validators }}
+Validators: []validator.{{ .ValidatorType }}{
+  {{- if .GenAIValidatorType }}
+  {{ .GenAIValidatorType }}{},
+  {{- end }}
+  {{- range .Validators }}
+  {{ . }},
+  {{- end }}
 },
 {{- end }}
 
 {{- if .NestedAttributes }}
-  {{- if eq .AttributeType "ListNestedAttribute" }}
+  {{- if or (eq .AttributeType "ListNestedAttribute") (eq .AttributeType "SetNestedAttribute") }}
   NestedObject: schema.NestedAttributeObject{
-    {{ .NestedAttributes }}
+    {{ .NestedAttributes.Render }}
   },
   {{- else }}
-  {{ .NestedAttributes }}
+  {{ .NestedAttributes.Render }}
   {{- end }}
 {{- end }}
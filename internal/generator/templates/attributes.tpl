@@ -1,7 +1,7 @@
 Attributes: map[string]schema.Attribute{
     {{- range $val := . }}
     "{{- $val.Name }}": schema.{{ $val.AttributeType }}{ 
-        {{ $val }} 
+        {{ $val.Render }} 
     },
     {{- end }}
 },
\ No newline at end of file
@@ -1,12 +1,12 @@
 {{- if .ElementType -}}
-  {{- if eq .AttributeType "ListAttribute" -}}
+  {{- if or (eq .AttributeType "ListAttribute") (eq .AttributeType "SetAttribute") -}}
     {{ .FieldName }} []types.{{ .ElementType }} `tfsdk:"{{ .AttributeName }}" manifest:"{{ .ManifestFieldName }}"`
   {{- else if eq .AttributeType "MapAttribute" -}}
     {{ .FieldName }} map[string]types.{{ .ElementType }} `tfsdk:"{{ .AttributeName }}" manifest:"{{ .ManifestFieldName }}"`
   {{- end -}}
 {{- else if .NestedFields -}}
-  {{ .FieldName }} {{ if eq .AttributeType "ListNestedAttribute" -}}[]{{- end -}}struct{
-    {{ .NestedFields }}
+  {{ .FieldName }} {{ if or (eq .AttributeType "ListNestedAttribute") (eq .AttributeType "SetNestedAttribute") -}}[]{{- end -}}struct{
+    {{ .NestedFields.Render }}
   } `tfsdk:"{{ .AttributeName }}" manifest:"{{ .ManifestFieldName }}"`
 {{- else -}}
   {{ .FieldName }} types.{{ .Type }} `tfsdk:"{{ .AttributeName }}" manifest:"{{ .ManifestFieldName }}"`
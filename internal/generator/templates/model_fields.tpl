@@ -1,3 +1,3 @@
 {{- range $val := . }}
-  {{ $val }}
+  {{ $val.Render }}
 {{- end }}
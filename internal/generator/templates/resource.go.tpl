@@ -1,3 +1,5 @@
+{{ .Provider.LicenseComment }}// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
+
 package {{ .ResourceConfig.Package }}
 
 import (
@@ -5,7 +7,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
-	"github.com/hashicorp/terraform-provider-kubernetes/internal/framework/provider/client"
+	"{{ .Provider.ClientImportPath }}"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -1,3 +1,5 @@
+{{ .Provider.LicenseComment }}// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
+
 package {{ .ResourceConfig.Package }}
 
 import (
@@ -12,6 +14,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+{{- if .HookMethods }}
+
+// the hooks enabled in the generator configuration must implement the autocrud hook interfaces
+var (
+{{- range .HookMethods }}
+	_ autocrud.{{ . }}Hook[{{ $.ResourceConfig.Kind }}Model] = &{{ $.ResourceConfig.Kind }}{}
+{{- end }}
+)
+{{- end }}
+
 func (r *{{ .ResourceConfig.Kind }}) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var dataModel {{ .ResourceConfig.Kind }}Model
 
@@ -34,31 +46,28 @@ func (r *{{ .ResourceConfig.Kind }}) Create(ctx context.Context, req resource.Cr
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook.Create -}}
-	r.BeforeCreate(ctx, req, resp, &dataModel)
-	{{ end }}
-	{{ end }}
-	{{ end }}
-	{{ end }}
-
-	err = autocrud.Create(ctx, r.clientGetter, r.APIVersion, r.Kind, &dataModel)
+	manifest := autocrud.ExpandManifest(r.APIVersion, r.Kind, &dataModel)
+	if hook, ok := any(r).(autocrud.BeforeCreateHook[{{ .ResourceConfig.Kind }}Model]); ok {
+		expanded := manifest.DeepCopy()
+		hook.BeforeCreate(ctx, req, resp, &dataModel, manifest)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		manifest = autocrud.ExpandManifestAfterHook(r.APIVersion, r.Kind, &dataModel, expanded, manifest)
+	}
+
+	res, err := autocrud.ApplyManifest(ctx, r.clientGetter, manifest, &dataModel)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating resource", err.Error())
 		return
 	}
 
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook.Create -}}
-	r.AfterCreate(ctx, req, resp, &dataModel)
-	{{ end }}
-	{{ end }}
-	{{ end }}
-	{{ end }}
+	if hook, ok := any(r).(autocrud.AfterCreateHook[{{ .ResourceConfig.Kind }}Model]); ok {
+		hook.AfterCreate(ctx, req, resp, &dataModel, res)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	diags := resp.State.Set(ctx, &dataModel)
 	resp.Diagnostics.Append(diags...)
@@ -89,33 +98,27 @@ func (r *{{ .ResourceConfig.Kind }}) Read(ctx context.Context, req resource.Read
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook.Read -}}
-	r.BeforeRead(ctx, req, resp, &dataModel)
-	{{ end }}
-	{{ end }}
-	{{ end }}
-	{{ end }}
+	if hook, ok := any(r).(autocrud.BeforeReadHook[{{ .ResourceConfig.Kind }}Model]); ok {
+		hook.BeforeRead(ctx, req, resp, &dataModel, autocrud.ExpandManifest(r.APIVersion, r.Kind, &dataModel))
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	var id string
     req.State.GetAttribute(ctx, path.Root("id"), &id)
-	err = autocrud.Read(ctx, r.clientGetter, r.Kind, r.APIVersion, id, &dataModel)
+	res, err := autocrud.ReadManifest(ctx, r.clientGetter, r.Kind, r.APIVersion, id, &dataModel)
 	if err != nil {
 		resp.Diagnostics.AddError("Error reading resource", err.Error())
 		return
 	}
 
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook.Read -}}
-	r.AfterRead(ctx, req, resp, &dataModel)
-	{{ end }}
-	{{ end }}
-	{{ end }}
-	{{ end }}
+	if hook, ok := any(r).(autocrud.AfterReadHook[{{ .ResourceConfig.Kind }}Model]); ok {
+		hook.AfterRead(ctx, req, resp, &dataModel, res)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	diags := resp.State.Set(ctx, &dataModel)
 	resp.Diagnostics.Append(diags...)
@@ -146,31 +149,28 @@ func (r *{{ .ResourceConfig.Kind }}) Update(ctx context.Context, req resource.Up
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook.Update -}}
-	r.BeforeUpdate(ctx, req, resp, &dataModel)
-	{{ end }}
-	{{ end }}
-	{{ end }}
-	{{ end }}
-
-	err = autocrud.Update(ctx, r.clientGetter, r.Kind, r.APIVersion, &dataModel)
+	manifest := autocrud.ExpandManifest(r.APIVersion, r.Kind, &dataModel)
+	if hook, ok := any(r).(autocrud.BeforeUpdateHook[{{ .ResourceConfig.Kind }}Model]); ok {
+		expanded := manifest.DeepCopy()
+		hook.BeforeUpdate(ctx, req, resp, &dataModel, manifest)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		manifest = autocrud.ExpandManifestAfterHook(r.APIVersion, r.Kind, &dataModel, expanded, manifest)
+	}
+
+	res, err := autocrud.ApplyManifest(ctx, r.clientGetter, manifest, &dataModel)
 	if err != nil {
 		resp.Diagnostics.AddError("Error updating resource", err.Error())
 		return
 	}
 
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook.Update -}}
-	r.AfterUpdate(ctx, req, resp, &dataModel)
-	{{ end }}
-	{{ end }}
-	{{ end }}
-	{{ end }}
+	if hook, ok := any(r).(autocrud.AfterUpdateHook[{{ .ResourceConfig.Kind }}Model]); ok {
+		hook.AfterUpdate(ctx, req, resp, &dataModel, res)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	diags := resp.State.Set(ctx, &dataModel)
 	resp.Diagnostics.Append(diags...)
@@ -207,15 +207,13 @@ func (r *{{ .ResourceConfig.Kind }}) Delete(ctx context.Context, req resource.De
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook.Delete -}}
-	r.BeforeDelete(ctx, req, resp, &dataModel)
-	{{ end }}
-	{{ end }}
-	{{ end }}
-	{{ end }}
+	manifest := autocrud.ExpandManifest(r.APIVersion, r.Kind, &dataModel)
+	if hook, ok := any(r).(autocrud.BeforeDeleteHook[{{ .ResourceConfig.Kind }}Model]); ok {
+		hook.BeforeDelete(ctx, req, resp, &dataModel, manifest)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	err = autocrud.Delete(ctx, r.clientGetter, r.Kind, r.APIVersion, req, waitForDeletion)
 	if err != nil {
@@ -223,15 +221,12 @@ func (r *{{ .ResourceConfig.Kind }}) Delete(ctx context.Context, req resource.De
 		return
 	}
 
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook -}}
-	{{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook.Delete -}}
-	r.AfterDelete(ctx, req, resp, &dataModel)
-	{{ end }}
-	{{ end }}
-	{{ end }}
-	{{ end }}
+	if hook, ok := any(r).(autocrud.AfterDeleteHook[{{ .ResourceConfig.Kind }}Model]); ok {
+		hook.AfterDelete(ctx, req, resp, &dataModel, manifest)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 }
 
 func (r *{{ .ResourceConfig.Kind }}) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
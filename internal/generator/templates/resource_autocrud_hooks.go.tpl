@@ -1,9 +1,13 @@
-// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; THIS FILE IS FOR YOU TO EDIT
+{{ .Provider.LicenseComment }}// Code scaffolded by hashicorp/terraform-plugin-codegen-kubernetes; THIS FILE IS FOR YOU TO EDIT.
 //
-// This file contains function signatures for implementing CRUD hooks. 
-// You need to provide the implementation for these functions. 
+// This file contains function signatures for implementing CRUD hooks.
+// You need to provide the implementation for these functions. It is
+// only written once, the generator will not overwrite it. Adding an
+// error to the response diagnostics in a hook stops the operation.
+{{- if .GeneratedTimestamp }}
 //
 // This code was written by a robot on {{ .GeneratedTimestamp.Format "Jan 02, 2006 15:04:05 UTC" }}.
+{{- end }}
 
 package {{ .ResourceConfig.Package }}
 
@@ -11,11 +15,13 @@ import (
 	"context"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook.Create -}}
-func (r *{{ .ResourceConfig.Kind }}) BeforeCreate(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, m *{{ .ResourceConfig.Kind }}Model) {
+// BeforeCreate implements autocrud.BeforeCreateHook
+func (r *{{ .ResourceConfig.Kind }}) BeforeCreate(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, m *{{ .ResourceConfig.Kind }}Model, manifest *unstructured.Unstructured) {
 	// TODO: Add BeforeCreate logic
 }
 {{ end }}
@@ -25,7 +31,8 @@ func (r *{{ .ResourceConfig.Kind }}) BeforeCreate(ctx context.Context, req resou
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook.Create -}}
-func (r *{{ .ResourceConfig.Kind }}) AfterCreate(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, m *{{ .ResourceConfig.Kind }}Model) {
+// AfterCreate implements autocrud.AfterCreateHook
+func (r *{{ .ResourceConfig.Kind }}) AfterCreate(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, m *{{ .ResourceConfig.Kind }}Model, manifest *unstructured.Unstructured) {
     // TODO: Add AfterCreate logic
 }
 {{ end }}
@@ -35,7 +42,8 @@ func (r *{{ .ResourceConfig.Kind }}) AfterCreate(ctx context.Context, req resour
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook.Read -}}
-func (r *{{ .ResourceConfig.Kind }}) BeforeRead(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, m *{{ .ResourceConfig.Kind }}Model) {
+// BeforeRead implements autocrud.BeforeReadHook
+func (r *{{ .ResourceConfig.Kind }}) BeforeRead(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, m *{{ .ResourceConfig.Kind }}Model, manifest *unstructured.Unstructured) {
 	// TODO: Add BeforeRead logic
 }
 {{ end }}
@@ -45,7 +53,8 @@ func (r *{{ .ResourceConfig.Kind }}) BeforeRead(ctx context.Context, req resourc
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook.Read -}}
-func (r *{{ .ResourceConfig.Kind }}) AfterRead(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, m *{{ .ResourceConfig.Kind }}Model) {
+// AfterRead implements autocrud.AfterReadHook
+func (r *{{ .ResourceConfig.Kind }}) AfterRead(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, m *{{ .ResourceConfig.Kind }}Model, manifest *unstructured.Unstructured) {
     // TODO: Add AfterRead logic
 }
 {{ end }}
@@ -55,7 +64,8 @@ func (r *{{ .ResourceConfig.Kind }}) AfterRead(ctx context.Context, req resource
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook.Update -}}
-func (r *{{ .ResourceConfig.Kind }}) BeforeUpdate(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, m *{{ .ResourceConfig.Kind }}Model) {
+// BeforeUpdate implements autocrud.BeforeUpdateHook
+func (r *{{ .ResourceConfig.Kind }}) BeforeUpdate(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, m *{{ .ResourceConfig.Kind }}Model, manifest *unstructured.Unstructured) {
 	// TODO: Add BeforeUpdate logic
 }
 {{ end }}
@@ -65,7 +75,8 @@ func (r *{{ .ResourceConfig.Kind }}) BeforeUpdate(ctx context.Context, req resou
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook.Update -}}
-func (r *{{ .ResourceConfig.Kind }}) AfterUpdate(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, m *{{ .ResourceConfig.Kind }}Model) {
+// AfterUpdate implements autocrud.AfterUpdateHook
+func (r *{{ .ResourceConfig.Kind }}) AfterUpdate(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, m *{{ .ResourceConfig.Kind }}Model, manifest *unstructured.Unstructured) {
     // TODO: Add AfterUpdate logic
 }
 {{ end }}
@@ -75,7 +86,8 @@ func (r *{{ .ResourceConfig.Kind }}) AfterUpdate(ctx context.Context, req resour
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.BeforeHook.Delete -}}
-func (r *{{ .ResourceConfig.Kind }}) BeforeDelete(ctx context.Context, req resource.DestroyRequest, resp *resource.DestroyResponse, m *{{ .ResourceConfig.Kind }}Model) {
+// BeforeDelete implements autocrud.BeforeDeleteHook
+func (r *{{ .ResourceConfig.Kind }}) BeforeDelete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse, m *{{ .ResourceConfig.Kind }}Model, manifest *unstructured.Unstructured) {
 	// TODO: Add BeforeDelete logic
 }
 {{ end }}
@@ -85,7 +97,8 @@ func (r *{{ .ResourceConfig.Kind }}) BeforeDelete(ctx context.Context, req resou
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook -}}
 {{ if .ResourceConfig.Generate.CRUDAutoOptions.Hooks.AfterHook.Delete -}}
-func (r *{{ .ResourceConfig.Kind }}) AfterDelete(ctx context.Context, req resource.DestroyRequest, resp *resource.DestroyResponse, m *{{ .ResourceConfig.Kind }}Model) {
+// AfterDelete implements autocrud.AfterDeleteHook
+func (r *{{ .ResourceConfig.Kind }}) AfterDelete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse, m *{{ .ResourceConfig.Kind }}Model, manifest *unstructured.Unstructured) {
     // TODO: Add AfterDelete logic
 }
 {{ end }}
@@ -0,0 +1,31 @@
+{{ .Provider.LicenseComment }}// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
+
+package {{ .ResourceConfig.Package }}
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/autocrud"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+var _ resource.ResourceWithConfigValidators = &{{ .ResourceConfig.Kind }}{}
+
+func (r *{{ .ResourceConfig.Kind }}) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		autocrud.ValidationRulesValidator[{{ .ResourceConfig.Kind }}Model]{
+			APIVersion: {{ printf "%q" .ResourceConfig.APIVersion }},
+			Kind:       {{ printf "%q" .ResourceConfig.Kind }},
+			Rules: []autocrud.ValidationRule{
+				{{- range .ValidationRules }}
+				{
+					AttributePath: {{ printf "%q" .AttributePath }},
+					ManifestPath:  {{ printf "%q" .ManifestPath }},
+					Rule:          {{ printf "%q" .Rule }},
+					Message:       {{ printf "%q" .Message }},
+				},
+				{{- end }}
+			},
+		},
+	}
+}
@@ -1,3 +1,7 @@
+{{ .Provider.LicenseComment }}// Code scaffolded by hashicorp/terraform-plugin-codegen-kubernetes; THIS FILE IS FOR YOU TO EDIT.
+//
+// This file contains stubs for the CRUD functions of the resource. It is
+// only written once, the generator will not overwrite it.
 
 package {{ .ResourceConfig.Package }}
 
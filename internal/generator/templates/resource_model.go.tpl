@@ -1,3 +1,5 @@
+{{ .Provider.LicenseComment }}// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
+
 package {{ .ResourceConfig.Package }}
 
 import (
@@ -7,5 +9,5 @@ import (
 
 type {{ .ResourceConfig.Kind }}Model struct {
   Timeouts    timeouts.Value `tfsdk:"timeouts"`
-  {{ .ModelFields }}
+  {{ .ModelFields.Render }}
 }
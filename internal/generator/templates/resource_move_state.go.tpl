@@ -0,0 +1,47 @@
+{{ .Provider.LicenseComment }}// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
+
+package {{ .ResourceConfig.Package }}
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/autocrud"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+var _ resource.ResourceWithMoveState = &{{ .ResourceConfig.Kind }}{}
+
+func (r *{{ .ResourceConfig.Kind }}) MoveState(ctx context.Context) []resource.StateMover {
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	stateType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	return []resource.StateMover{
+		{{- range $val := .ResourceConfig.MoveFrom }}
+		{
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if req.SourceTypeName != {{ printf "%q" $val.ResourceType }} {
+					return
+				}
+				if req.SourceSchemaVersion != {{ $val.SchemaVersion }} {
+					resp.Diagnostics.AddError("Unsupported source schema version",
+						fmt.Sprintf("Cannot move state from %s schema version %d, expected version %d", req.SourceTypeName, req.SourceSchemaVersion, {{ $val.SchemaVersion }}))
+					return
+				}
+				moves := []autocrud.AttributeMove{
+					{{- range $move := $val.Moves }}
+					{From: {{ printf "%q" $move.From }}, To: {{ printf "%q" $move.To }}, Key: {{ printf "%q" $move.Key }}, Unwrap: {{ $move.Unwrap }}},
+					{{- end }}
+				}
+				state, err := autocrud.MoveState(ctx, req.SourceRawState, stateType, moves)
+				if err != nil {
+					resp.Diagnostics.AddError("Error moving resource state", err.Error())
+					return
+				}
+				resp.TargetState.Raw = state
+			},
+		},
+		{{- end }}
+	}
+}
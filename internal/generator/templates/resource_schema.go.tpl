@@ -1,3 +1,5 @@
+{{ .Provider.LicenseComment }}// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
+
 package {{ .ResourceConfig.Package }}
 
 import (
@@ -11,11 +13,8 @@ import (
   {{- range $val := .Schema.Imports }}
   "{{ $val }}"
   {{- end }}
-  {{- if .ResourceConfig.Generate.GenAIValidation }}
-  "github.com/hashicorp/terraform-plugin-framework/schema/validator"
-  {{- end }}
 )
 
 func (r *{{ .ResourceConfig.Kind }}) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = {{ .Schema }}
+	resp.Schema = {{ .Schema.Render }}
 }
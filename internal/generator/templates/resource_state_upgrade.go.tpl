@@ -0,0 +1,44 @@
+{{ .Provider.LicenseComment }}// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
+
+package {{ .ResourceConfig.Package }}
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/autocrud"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	{{- if .StateUpgradesUseElementTypes }}
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	{{- end }}
+)
+
+var _ resource.ResourceWithUpgradeState = &{{ .ResourceConfig.Kind }}{}
+
+func (r *{{ .ResourceConfig.Kind }}) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	stateType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	return map[int64]resource.StateUpgrader{
+		{{- range $val := .StateUpgrades }}
+		{{ $val.Version }}: {
+			PriorSchema: &{{ $val.PriorSchema.Render }},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				moves := []autocrud.AttributeMove{
+					{{- range $move := $val.Moves }}
+					{From: {{ printf "%q" $move.From }}, To: {{ printf "%q" $move.To }}, Key: {{ printf "%q" $move.Key }}, Unwrap: {{ $move.Unwrap }}},
+					{{- end }}
+				}
+				state, err := autocrud.UpgradeState(ctx, req.RawState, stateType, moves)
+				if err != nil {
+					resp.Diagnostics.AddError("Error upgrading resource state", err.Error())
+					return
+				}
+				resp.State.Raw = state
+			},
+		},
+		{{- end }}
+	}
+}
@@ -0,0 +1,53 @@
+{{ .Provider.LicenseComment }}// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
+
+package {{ .ResourceConfig.Package }}
+
+{{- if .ValidatorTests }}
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	{{- range .ValidatorTestImports }}
+	"{{ . }}"
+	{{- end }}
+)
+{{- end }}
+{{ range .ValidatorTests }}
+func Test{{ $.ResourceConfig.Kind }}{{ .Interface }}Validators(t *testing.T) {
+	testCases := []struct {
+		name      string
+		validator validator.{{ .Interface }}
+		value     types.{{ .Interface }}
+		valid     bool
+	}{
+		{{- range .Cases }}
+		{
+			name:      {{ printf "%q" .Name }},
+			validator: {{ .Validator }},
+			value:     {{ .Value }},
+			valid:     {{ .Valid }},
+		},
+		{{- end }}
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.{{ .Interface }}Request{
+				Path:        path.Root("test"),
+				ConfigValue: tc.value,
+			}
+			resp := &validator.{{ .Interface }}Response{}
+			tc.validator.Validate{{ .Interface }}(context.Background(), req, resp)
+			if tc.valid && resp.Diagnostics.HasError() {
+				t.Errorf("expected %s to be valid: %v", tc.value, resp.Diagnostics)
+			}
+			if !tc.valid && !resp.Diagnostics.HasError() {
+				t.Errorf("expected %s to be invalid", tc.value)
+			}
+		})
+	}
+}
+{{ end }}
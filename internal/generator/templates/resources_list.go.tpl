@@ -1,16 +1,18 @@
-// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT 
+{{ .Provider.LicenseComment }}// Code generated by hashicorp/terraform-plugin-codegen-kubernetes; DO NOT EDIT.
 //
 // This file contains the list of constructors for resources that have been autogenerated.
+{{- if .GeneratedTimestamp }}
 //
 // This code was written by a robot on {{ .GeneratedTimestamp.Format "Jan 02, 2006 15:04:05 UTC" }}.
+{{- end }}
 
-package provider
+package {{ .Provider.PackageName }}
 
 import (
     "github.com/hashicorp/terraform-plugin-framework/resource"
 
 {{ range $val := .Packages }}
-    "github.com/hashicorp/terraform-provider-kubernetes/internal/framework/provider/{{ $val }}"
+    "{{ $.Provider.PackageImportPath }}/{{ $val }}"
 {{- end }}
 )
 
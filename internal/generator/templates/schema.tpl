@@ -1,7 +1,10 @@
 schema.Schema{
     MarkdownDescription: `{{ .Description }}`,
+    {{- if .Version }}
+    Version: {{ .Version }},
+    {{- end }}
     Blocks: map[string]schema.Block{
         "timeouts": timeouts.BlockAll(ctx),
     },
-    {{ .Attributes }}
+    {{ .Attributes.Render }}
 }
\ No newline at end of file
@@ -0,0 +1,29 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// generatedTimestamp returns the time to include in the header of generated
+// files. The time is only included when the SOURCE_DATE_EPOCH environment
+// variable is set so that the generated code is reproducible.
+// See: https://reproducible-builds.org/docs/source-date-epoch/
+func generatedTimestamp() *time.Time {
+	v, ok := os.LookupEnv("SOURCE_DATE_EPOCH")
+	if !ok || v == "" {
+		return nil
+	}
+	epoch, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		slog.Warn("Ignoring invalid SOURCE_DATE_EPOCH", "value", v, "err", err)
+		return nil
+	}
+	t := time.Unix(epoch, 0).UTC()
+	return &t
+}
@@ -31,3 +31,7 @@ func snakeToCamel(input string) string {
 
 	return result.String()
 }
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,80 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"log/slog"
+	"strings"
+
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+
+	"github.com/hashicorp/terraform-plugin-codegen-kubernetes/autocrud"
+)
+
+// ValidationRuleGenerator is a CEL rule from the x-kubernetes-validations
+// extension of the OpenAPI spec that is evaluated when planning
+type ValidationRuleGenerator struct {
+	AttributePath string
+	ManifestPath  string
+	Rule          string
+	Message       string
+}
+
+// GenerateValidationRules returns the x-kubernetes-validations rules in the
+// OpenAPI spec for the attributes of the resource, there are no rules if
+// the spec could not be read
+func GenerateValidationRules(doc *OpenAPIDocument, r ResourceConfig, spec specresource.Resource) []ValidationRuleGenerator {
+	if doc == nil {
+		return []ValidationRuleGenerator{}
+	}
+	return validationRules(doc, r, spec)
+}
+
+func validationRules(doc *OpenAPIDocument, r ResourceConfig, spec specresource.Resource) []ValidationRuleGenerator {
+	rules := []ValidationRuleGenerator{}
+	s := doc.requestSchema(r.OpenAPIConfig.CreatePath)
+	if s == nil {
+		slog.Warn("No request body schema found in OpenAPI spec, skipping validation rules", "resource", r.Name, "path", r.OpenAPIConfig.CreatePath)
+		return rules
+	}
+	rules = appendValidationRules(rules, r, s, "")
+	walkOpenAPIAttributes(doc, spec.Schema.Attributes, s, "", func(attr *specresource.Attribute, prop *openAPISchema, attributePath string) {
+		rules = appendValidationRules(rules, r, prop, attributePath)
+		if attr.ListNested != nil || attr.SetNested != nil {
+			rules = appendValidationRules(rules, r, doc.resolve(prop.Items), attributePath+"[*]")
+		}
+	})
+	return rules
+}
+
+// appendValidationRules appends the rules of the schema, transition rules
+// that use oldSelf are skipped as there is no prior value when validating
+// the configuration, and rules that do not compile are skipped as they could
+// never be evaluated
+func appendValidationRules(rules []ValidationRuleGenerator, r ResourceConfig, s *openAPISchema, specPath string) []ValidationRuleGenerator {
+	if s == nil || len(s.ValidationRules) == 0 {
+		return rules
+	}
+	attributePath, manifestPath, ok := r.schemaPath(specPath)
+	if !ok {
+		return rules
+	}
+	for _, v := range s.ValidationRules {
+		if strings.Contains(v.Rule, "oldSelf") {
+			slog.Debug("Skipping transition rule", "resource", r.Name, "path", specPath, "rule", v.Rule)
+			continue
+		}
+		if err := autocrud.CompileValidationRule(v.Rule); err != nil {
+			slog.Warn("Skipping validation rule that does not compile, it may use a function from the Kubernetes CEL libraries", "resource", r.Name, "path", specPath, "rule", v.Rule, "err", err)
+			continue
+		}
+		rules = append(rules, ValidationRuleGenerator{
+			AttributePath: attributePath,
+			ManifestPath:  manifestPath,
+			Rule:          v.Rule,
+			Message:       v.Message,
+		})
+	}
+	return rules
+}
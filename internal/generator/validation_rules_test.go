@@ -0,0 +1,51 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidationRules(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testResourceConfig(t)
+	cfg.OpenAPIConfig.Filename = "testdata/widget_openapi.json"
+	cfg.OpenAPIConfig.CreatePath = "/apis/example.com/v1/namespaces/{namespace}/widgets"
+	cfg.Attributes = append(cfg.Attributes, AttributeConfig{Path: "spec.ports", Rename: "port"})
+
+	doc, err := readOpenAPIDocument(cfg.OpenAPIConfig.Filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := GenerateValidationRules(doc, cfg, spec)
+	expected := []ValidationRuleGenerator{
+		{AttributePath: "spec", ManifestPath: "spec", Rule: "!has(self.ports) || size(self.ports) <= self.replicas * 2", Message: "at most two ports per replica"},
+		{AttributePath: "spec.port[*]", ManifestPath: "spec.ports[*]", Rule: "self.containerPort != 22"},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Fatalf("expected %v got %v", expected, rules)
+	}
+
+	gen := NewResourceGenerator(DefaultProviderConfig(), cfg, spec)
+	gen.ValidationRules = rules
+	code, err := gen.GenerateCELValidationCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`autocrud.ValidationRulesValidator[WidgetModel]{`,
+		`AttributePath: "spec.port[*]",`,
+		`ManifestPath:  "spec.ports[*]",`,
+		`Rule:          "!has(self.ports) || size(self.ports) <= self.replicas * 2",`,
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("expected code to contain %q", expected)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"sort"
+)
+
+// ValidatorCache stores generated validators so they are reused on later
+// runs. It is written as JSON so the code can be reviewed and edited.
+type ValidatorCache struct {
+	Validators []CachedValidator `json:"validators"`
+}
+
+// CachedValidator is the code generated for a validator type from the
+// description of an attribute by a model, and the examples it is tested with
+type CachedValidator struct {
+	Type            string            `json:"type"`
+	DescriptionHash string            `json:"description_hash"`
+	Model           string            `json:"model"`
+	Code            string            `json:"code"`
+	Examples        ValidatorExamples `json:"examples"`
+}
+
+// ReadValidatorCache reads the cache file, a cache that does not exist is empty
+func ReadValidatorCache(filename string) (*ValidatorCache, error) {
+	cache := &ValidatorCache{}
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cache, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, cache); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+	return cache, nil
+}
+
+// Marshal returns the cache as JSON with the validators sorted by type
+func (c *ValidatorCache) Marshal() ([]byte, error) {
+	if c.Validators == nil {
+		c.Validators = []CachedValidator{}
+	}
+	sort.SliceStable(c.Validators, func(i, j int) bool {
+		return c.Validators[i].Type < c.Validators[j].Type
+	})
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func (c *ValidatorCache) lookup(validatorType, descriptionHash, model string) (CachedValidator, bool) {
+	for _, v := range c.Validators {
+		if v.Type == validatorType && v.DescriptionHash == descriptionHash && v.Model == model {
+			return v, true
+		}
+	}
+	return CachedValidator{}, false
+}
+
+func descriptionHash(description string) string {
+	hash := sha256.Sum256([]byte(description))
+	return hex.EncodeToString(hash[:])
+}
+
+// CachedValidatorGenerator reuses the validators in a cache and only asks
+// the Generator for validators that are not in it. A validator is looked up
+// by its type, the hash of the attribute description and the model, so it is
+// generated again when the description or model changes.
+type CachedValidatorGenerator struct {
+	Generator ValidatorGenerator
+	Model     string
+	Cache     *ValidatorCache
+
+	// Refresh generates every validator again instead of using the cache
+	Refresh bool
+
+	// Check type checks the cached validators, as they can be edited by hand.
+	// Cached validators that do not compile are left out with a warning.
+	Check bool
+
+	// Review adds new validators to Suggestions instead of returning them,
+	// so they are left out of the generated code until they have been
+	// reviewed and moved to the cache
+	Review      bool
+	Suggestions ValidatorCache
+
+	// used contains the validators returned, it replaces the cache once all
+	// the validators have been generated so that unused entries are removed
+	used ValidatorCache
+}
+
+var _ ValidatorGenerator = &CachedValidatorGenerator{}
+
+func (g *CachedValidatorGenerator) GenerateValidator(ctx context.Context, req ValidatorRequest) (GeneratedValidator, error) {
+	validatorType, hash := req.Type, descriptionHash(req.Description)
+	cached, ok := g.Cache.lookup(validatorType, hash, g.Model)
+	if ok && !g.Refresh {
+		slog.Debug("Using cached validator", "validator", validatorType)
+		g.used.Validators = append(g.used.Validators, cached)
+		return g.checkCached(req, cached), nil
+	}
+
+	generated, err := g.Generator.GenerateValidator(ctx, req)
+	if err != nil || generated.Code == "" {
+		return generated, err
+	}
+	v := CachedValidator{validatorType, hash, g.Model, generated.Code, generated.Examples}
+	if !g.Review {
+		g.used.Validators = append(g.used.Validators, v)
+		return generated, nil
+	}
+
+	// the reviewed validator is used until the suggestion replaces it
+	g.Suggestions.Validators = append(g.Suggestions.Validators, v)
+	if ok {
+		g.used.Validators = append(g.used.Validators, cached)
+		return g.checkCached(req, cached), nil
+	}
+	return GeneratedValidator{}, nil
+}
+
+// checkCached returns the cached validator if it compiles. Code that does
+// not compile is kept in the cache so it can be fixed by hand.
+func (g *CachedValidatorGenerator) checkCached(req ValidatorRequest, cached CachedValidator) GeneratedValidator {
+	v := GeneratedValidator{Code: cached.Code, Examples: cached.Examples}
+	if !g.Check {
+		return v
+	}
+	err := checkValidator(req, v.Code)
+	if errors.Is(err, errValidatorCheckUnavailable) {
+		slog.Warn("Skipping check of cached validator", "validator", req.Type, "err", err)
+		return v
+	} else if err != nil {
+		slog.Warn("Leaving out cached validator that does not compile, fix it in the cache or use -refresh-validators", "validator", req.Type, "err", err)
+		return GeneratedValidator{}
+	}
+	return v
+}
+
+// UpdatedCache returns the cache with only the validators that were
+// returned, so entries for attributes that have changed are removed
+func (g *CachedValidatorGenerator) UpdatedCache() *ValidatorCache {
+	return &g.used
+}
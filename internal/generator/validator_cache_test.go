@@ -0,0 +1,80 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCachedValidatorGenerator(t *testing.T) {
+	ctx := context.Background()
+	cache, err := ReadValidatorCache(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Validators = []CachedValidator{
+		{Type: "name_validator", DescriptionHash: descriptionHash("The name."), Model: "gpt-4o", Code: "// reviewed", Examples: ValidatorExamples{
+			Valid: []json.RawMessage{json.RawMessage(`"abc"`)},
+		}},
+		{Type: "port_validator", DescriptionHash: descriptionHash("The port."), Model: "gpt-4o", Code: "// removed"},
+	}
+
+	backend := &fakeValidatorGenerator{examples: ValidatorExamples{Invalid: []json.RawMessage{json.RawMessage(`-1`)}}}
+	g := &CachedValidatorGenerator{Generator: backend, Model: "gpt-4o", Cache: cache}
+	for _, tc := range []struct {
+		validatorType string
+		description   string
+		expected      string
+		examples      ValidatorExamples
+	}{
+		{"name_validator", "The name.", "// reviewed", cache.Validators[0].Examples},
+		{"replicas_validator", "The replicas.", "type replicas_validator struct{}", backend.examples},
+	} {
+		v, err := g.GenerateValidator(ctx, ValidatorRequest{Type: tc.validatorType, Description: tc.description})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Code != tc.expected {
+			t.Errorf("expected %q got %q", tc.expected, v.Code)
+		}
+		if !reflect.DeepEqual(v.Examples, tc.examples) {
+			t.Errorf("expected examples %v got %v", tc.examples, v.Examples)
+		}
+	}
+	if len(backend.calls) != 1 || backend.calls[0].Type != "replicas_validator" {
+		t.Errorf("expected only the validator missing from the cache to be generated, got %v", backend.calls)
+	}
+	types := []string{}
+	for _, v := range g.UpdatedCache().Validators {
+		types = append(types, v.Type)
+		if v.Type == "replicas_validator" && !reflect.DeepEqual(v.Examples, backend.examples) {
+			t.Errorf("expected the examples to be cached got %v", v.Examples)
+		}
+	}
+	if !reflect.DeepEqual(types, []string{"name_validator", "replicas_validator"}) {
+		t.Errorf("expected the unused validator to be removed from the cache, got %v", types)
+	}
+
+	// a different model or description misses the cache
+	g = &CachedValidatorGenerator{Generator: backend, Model: "local-model", Cache: cache}
+	if v, _ := g.GenerateValidator(ctx, ValidatorRequest{Type: "name_validator", Description: "The name."}); v.Code != "type name_validator struct{}" {
+		t.Errorf("expected the validator to be generated for a different model, got %q", v.Code)
+	}
+
+	// new validators are held back for review
+	g = &CachedValidatorGenerator{Generator: backend, Model: "gpt-4o", Cache: cache, Review: true, Refresh: true}
+	if v, _ := g.GenerateValidator(ctx, ValidatorRequest{Type: "name_validator", Description: "The name."}); v.Code != "// reviewed" {
+		t.Errorf("expected the reviewed validator to be used until the suggestion is reviewed, got %q", v.Code)
+	}
+	if v, _ := g.GenerateValidator(ctx, ValidatorRequest{Type: "replicas_validator", Description: "The replicas."}); v.Code != "" {
+		t.Errorf("expected no code for a validator that has not been reviewed, got %q", v.Code)
+	}
+	if len(g.Suggestions.Validators) != 2 {
+		t.Errorf("expected 2 suggestions got %d", len(g.Suggestions.Validators))
+	}
+}
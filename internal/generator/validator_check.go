@@ -0,0 +1,189 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxValidatorFixes is the number of times a model is asked to fix a
+// validator that does not compile before it is dropped
+const DefaultMaxValidatorFixes = 2
+
+// maxValidatorErrors limits the number of compiler errors sent to the model
+const maxValidatorErrors = 10
+
+// validatorPackages are the packages generated validators can use, they are
+// imported when the code refers to them as the model is told not to write imports
+var validatorPackages = map[string]string{
+	"context":   "context",
+	"fmt":       "fmt",
+	"math":      "math",
+	"net":       "net",
+	"regexp":    "regexp",
+	"strconv":   "strconv",
+	"strings":   "strings",
+	"time":      "time",
+	"unicode":   "unicode",
+	"utf8":      "unicode/utf8",
+	"diag":      "github.com/hashicorp/terraform-plugin-framework/diag",
+	"path":      "github.com/hashicorp/terraform-plugin-framework/path",
+	"types":     "github.com/hashicorp/terraform-plugin-framework/types",
+	"validator": validatorImportPath,
+}
+
+// validatorsSource returns the source of a file containing the validators
+// with imports for the packages they use
+func validatorsSource(pkg string, validators []string) string {
+	used := map[string]bool{}
+	for _, v := range validators {
+		f, err := parser.ParseFile(token.NewFileSet(), "", "package "+pkg+"\n"+v, 0)
+		if err != nil {
+			// the type check reports the syntax error
+			continue
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				// identifiers that are declared in the code are resolved by the parser
+				if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+					if importPath, ok := validatorPackages[id.Name]; ok {
+						used[importPath] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	std, other := []string{}, []string{}
+	for importPath := range used {
+		if strings.Contains(importPath, ".") {
+			other = append(other, fmt.Sprintf("\t%q\n", importPath))
+		} else {
+			std = append(std, fmt.Sprintf("\t%q\n", importPath))
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	imports := strings.Join(std, "")
+	if len(std) > 0 && len(other) > 0 {
+		imports += "\n"
+	}
+	imports += strings.Join(other, "")
+
+	src := fmt.Sprintf(fileHeader, pkg, imports)
+	for _, v := range validators {
+		src += "\n\n" + v + "\n"
+	}
+	return src
+}
+
+// validatorChecker type checks validators, it is shared as importing the
+// framework packages from source is slow
+var validatorChecker struct {
+	sync.Mutex
+	fset      *token.FileSet
+	importer  types.Importer
+	importErr error
+}
+
+// errValidatorCheckUnavailable is returned when the framework packages can't
+// be imported, e.g. when the generator is not run in a provider module
+var errValidatorCheckUnavailable = errors.New("the framework validator package could not be imported")
+
+// checkValidator type checks the code for a validator on its own and
+// checks it implements the validator interface
+func checkValidator(req ValidatorRequest, code string) error {
+	return checkValidators("validators", []ValidatorRequest{req}, []string{code})
+}
+
+// checkValidators type checks the validators in a single file, as they are
+// written to the generated code, and checks they implement their interfaces
+func checkValidators(pkg string, reqs []ValidatorRequest, validators []string) error {
+	validatorChecker.Lock()
+	defer validatorChecker.Unlock()
+
+	if validatorChecker.importer == nil {
+		validatorChecker.fset = token.NewFileSet()
+		validatorChecker.importer = importer.ForCompiler(validatorChecker.fset, "source", nil)
+		if _, err := validatorChecker.importer.Import(validatorImportPath); err != nil {
+			validatorChecker.importErr = fmt.Errorf("%w: %v", errValidatorCheckUnavailable, err)
+		}
+	}
+	if validatorChecker.importErr != nil {
+		return validatorChecker.importErr
+	}
+
+	code := append([]string{}, validators...)
+	for _, req := range reqs {
+		code = append(code, fmt.Sprintf("var _ validator.%s = %s{}", req.Interface, req.Type))
+	}
+	src := validatorsSource(pkg, code)
+	f, err := parser.ParseFile(validatorChecker.fset, "validators.go", src, 0)
+	if err != nil {
+		return err
+	}
+	errs := []string{}
+	conf := types.Config{
+		Importer: validatorChecker.importer,
+		Error: func(err error) {
+			if len(errs) < maxValidatorErrors {
+				errs = append(errs, err.Error())
+			}
+		},
+	}
+	conf.Check(pkg, validatorChecker.fset, []*ast.File{f}, nil)
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// CheckedValidatorGenerator type checks the code from the Generator and
+// asks for code that does not compile to be fixed. Validators that still
+// don't compile after MaxFixes attempts are dropped with a warning.
+type CheckedValidatorGenerator struct {
+	Generator ValidatorGenerator
+	MaxFixes  int
+}
+
+var _ ValidatorGenerator = &CheckedValidatorGenerator{}
+
+func (g *CheckedValidatorGenerator) GenerateValidator(ctx context.Context, req ValidatorRequest) (GeneratedValidator, error) {
+	v, err := g.Generator.GenerateValidator(ctx, req)
+	examples := v.Examples
+	for fixes := 0; ; fixes++ {
+		if err != nil || v.Code == "" {
+			return v, err
+		}
+		// the examples are only generated with the first code
+		v.Examples = examples
+		checkErr := checkValidator(req, v.Code)
+		if errors.Is(checkErr, errValidatorCheckUnavailable) {
+			slog.Warn("Skipping check of generated validator", "validator", req.Type, "err", checkErr)
+			return v, nil
+		} else if checkErr == nil {
+			return v, nil
+		}
+		if fixes == g.MaxFixes {
+			slog.Warn("Dropping generated validator that does not compile", "validator", req.Type, "err", checkErr)
+			return GeneratedValidator{}, nil
+		}
+		slog.Debug("Generated validator does not compile, asking for it to be fixed", "validator", req.Type, "err", checkErr)
+		fix := req
+		fix.Code, fix.Error = v.Code, checkErr.Error()
+		v, err = g.Generator.GenerateValidator(ctx, fix)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const testStringValidator = `type name_validator struct{}
+
+func (v name_validator) Description(ctx context.Context) string {
+	return "name must start with a letter"
+}
+
+func (v name_validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v name_validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if !regexp.MustCompile("^[a-z]").MatchString(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid name", fmt.Sprintf("%q must start with a letter", req.ConfigValue.ValueString()))
+	}
+}`
+
+func TestValidatorsSource(t *testing.T) {
+	src := validatorsSource("widget", []string{testStringValidator})
+	expected := "import (\n\t\"context\"\n\t\"fmt\"\n\t\"regexp\"\n\n\t\"github.com/hashicorp/terraform-plugin-framework/schema/validator\"\n)\n"
+	if !strings.Contains(src, expected) {
+		t.Errorf("expected imports %q in %q", expected, src)
+	}
+}
+
+func TestCheckedValidatorGenerator(t *testing.T) {
+	req := ValidatorRequest{Type: "name_validator", Interface: "String", Description: "The name."}
+	wrongInterface := strings.Replace(testStringValidator, "ValidateString", "ValidateInt64", 1)
+
+	backend := &fakeValidatorGenerator{codes: []string{wrongInterface, testStringValidator}}
+	g := &CheckedValidatorGenerator{Generator: backend, MaxFixes: 1}
+	v, err := g.GenerateValidator(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Code != testStringValidator {
+		t.Errorf("expected the fixed validator got %q", v.Code)
+	}
+	if len(backend.calls) != 2 {
+		t.Fatalf("expected 2 calls got %d", len(backend.calls))
+	}
+	if fix := backend.calls[1]; fix.Code != wrongInterface || !strings.Contains(fix.Error, "missing method ValidateString") {
+		t.Errorf("expected the code and compiler error to be sent, got %q", fix.Error)
+	}
+
+	backend = &fakeValidatorGenerator{codes: []string{wrongInterface, "type name_validator struct{", wrongInterface}}
+	g = &CheckedValidatorGenerator{Generator: backend, MaxFixes: 2}
+	v, err = g.GenerateValidator(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Code != "" {
+		t.Errorf("expected the validator to be dropped got %q", v.Code)
+	}
+	if len(backend.calls) != 3 {
+		t.Errorf("expected 3 calls got %d", len(backend.calls))
+	}
+}
+
+func TestCheckCachedValidator(t *testing.T) {
+	ctx := context.Background()
+	req := ValidatorRequest{Type: "name_validator", Interface: "String", Description: "The name."}
+	cache := &ValidatorCache{Validators: []CachedValidator{
+		{Type: "name_validator", DescriptionHash: descriptionHash("The name."), Model: "gpt-4o", Code: "type name_validator struct{"},
+	}}
+
+	backend := &fakeValidatorGenerator{}
+	g := &CachedValidatorGenerator{Generator: backend, Model: "gpt-4o", Cache: cache, Check: true}
+	v, err := g.GenerateValidator(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Code != "" {
+		t.Errorf("expected the cached validator to be left out got %q", v.Code)
+	}
+	if len(backend.calls) != 0 {
+		t.Errorf("expected the model not to be used got %v", backend.calls)
+	}
+	if len(g.UpdatedCache().Validators) != 1 {
+		t.Errorf("expected the cached validator to be kept so it can be fixed")
+	}
+}
+
+func TestCheckGenAIValidators(t *testing.T) {
+	helper := "\n\nfunc startsWithLetter(s string) bool { return regexp.MustCompile(\"^[a-z]\").MatchString(s) }"
+	other := strings.ReplaceAll(testStringValidator, "name_validator", "image_validator")
+	g := &ResourceGenerator{
+		ResourceConfig: ResourceConfig{Name: "kubernetes_widget_v1", Package: "widget"},
+		Schema: SchemaGenerator{Attributes: AttributesGenerator{
+			{Name: "name", ValidatorType: StringValidatorType, GenAIValidatorType: "name_validator"},
+			{Name: "image", ValidatorType: StringValidatorType, GenAIValidatorType: "image_validator"},
+		}},
+		GenAIValidators: []string{testStringValidator, other},
+	}
+	if err := g.CheckGenAIValidators(); err != nil {
+		t.Errorf("expected the validators to compile got %v", err)
+	}
+
+	// each validator compiles on its own but they declare the same helper
+	g.GenAIValidators = []string{testStringValidator + helper, other + helper}
+	if err := g.CheckGenAIValidators(); err == nil || !strings.Contains(err.Error(), "startsWithLetter redeclared") {
+		t.Errorf("expected the duplicate helper to be reported got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// DefaultValidatorModel is the model used to generate validators when one is not configured
+const DefaultValidatorModel = openai.GPT4o
+
+// ValidatorRequest describes a validator to generate
+type ValidatorRequest struct {
+	// Type is the name of the validator type
+	Type string
+
+	// Interface is the validator interface the type implements, e.g. String
+	// for validator.String
+	Interface string
+
+	// Description is the description of the attribute
+	Description string
+
+	// Code and Error are set to the previous code and the reason it was
+	// rejected when asking for the code to be fixed, examples are not
+	// generated again for these requests
+	Code  string
+	Error string
+}
+
+// GeneratedValidator is the code for a validator type and the examples used
+// to test it
+type GeneratedValidator struct {
+	Code     string
+	Examples ValidatorExamples
+}
+
+// ValidatorExamples are JSON values of the attribute type that the
+// validator should accept and reject
+type ValidatorExamples struct {
+	Valid   []json.RawMessage `json:"valid,omitempty"`
+	Invalid []json.RawMessage `json:"invalid,omitempty"`
+}
+
+// ValidatorGenerator writes the Go code for a validator type from the
+// description of an attribute. It returns no code if the attribute should
+// not have a validator.
+type ValidatorGenerator interface {
+	GenerateValidator(ctx context.Context, req ValidatorRequest) (GeneratedValidator, error)
+}
+
+// OpenAIValidatorGenerator generates validators using a chat completions API
+// compatible with OpenAI's, such as a self-hosted model
+type OpenAIValidatorGenerator struct {
+	client *openai.Client
+	model  string
+}
+
+var _ ValidatorGenerator = &OpenAIValidatorGenerator{}
+
+// NewOpenAIValidatorGenerator creates an OpenAIValidatorGenerator for the API
+// at baseURL, e.g. https://api.openai.com/v1. The OpenAI API is used if
+// baseURL is empty and DefaultValidatorModel if model is empty.
+func NewOpenAIValidatorGenerator(baseURL, apiKey, model string) *OpenAIValidatorGenerator {
+	config := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	if model == "" {
+		model = DefaultValidatorModel
+	}
+	return &OpenAIValidatorGenerator{
+		client: openai.NewClientWithConfig(config),
+		model:  model,
+	}
+}
+
+// Model returns the name of the model used to generate validators
+func (g *OpenAIValidatorGenerator) Model() string {
+	return g.model
+}
+
+func (g *OpenAIValidatorGenerator) GenerateValidator(ctx context.Context, req ValidatorRequest) (GeneratedValidator, error) {
+	messages := []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: systemPrompt,
+		},
+		{
+			Role:    openai.ChatMessageRoleUser,
+			Content: fmt.Sprintf("type %s struct{} implements validator.%s. %s", req.Type, req.Interface, req.Description),
+		},
+	}
+	if req.Code != "" {
+		messages = append(messages,
+			openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleAssistant,
+				Content: req.Code,
+			},
+			openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleUser,
+				Content: fmt.Sprintf("The code does not compile, fix it and write the whole implementation again. The errors are:\n%s", req.Error),
+			},
+		)
+	}
+
+	code, err := g.complete(ctx, messages)
+	if err != nil {
+		return GeneratedValidator{}, fmt.Errorf("error generating validator %s: %v", req.Type, err)
+	}
+	if code == "" || req.Code != "" || !hasValidatorTests(req.Interface) {
+		return GeneratedValidator{Code: code}, nil
+	}
+
+	response, err := g.complete(ctx, []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: examplesPrompt,
+		},
+		{
+			Role:    openai.ChatMessageRoleUser,
+			Content: fmt.Sprintf("The attribute is a %s. %s", req.Interface, req.Description),
+		},
+	})
+	if err != nil {
+		return GeneratedValidator{}, fmt.Errorf("error generating examples for validator %s: %v", req.Type, err)
+	}
+	var examples ValidatorExamples
+	if err := json.Unmarshal([]byte(response), &examples); err != nil {
+		// the validator is still tested with null and unknown values
+		slog.Warn("Ignoring validator examples that are not valid JSON", "validator", req.Type, "err", err)
+	}
+	return GeneratedValidator{Code: code, Examples: examples}, nil
+}
+
+// complete returns the content of the first choice of a chat completion
+// without the markdown code block around it
+func (g *OpenAIValidatorGenerator) complete(ctx context.Context, messages []openai.ChatCompletionMessage) (string, error) {
+	resp, err := g.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:       g.model,
+		MaxTokens:   4095,
+		TopP:        0.3,
+		Temperature: 0.1,
+		Messages:    messages,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("the response has no choices")
+	}
+	return cleanResponse(resp.Choices[0].Message.Content), nil
+}
@@ -0,0 +1,195 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeValidatorGenerator returns codes in order, then the same code for a
+// validator type on every call, with the same examples
+type fakeValidatorGenerator struct {
+	err      error
+	codes    []string
+	examples ValidatorExamples
+	calls    []ValidatorRequest
+}
+
+func (g *fakeValidatorGenerator) GenerateValidator(ctx context.Context, req ValidatorRequest) (GeneratedValidator, error) {
+	g.calls = append(g.calls, req)
+	if g.err != nil {
+		return GeneratedValidator{}, g.err
+	}
+	if len(g.calls) <= len(g.codes) {
+		return GeneratedValidator{Code: g.codes[len(g.calls)-1], Examples: g.examples}, nil
+	}
+	return GeneratedValidator{Code: fmt.Sprintf("type %s struct{}", req.Type), Examples: g.examples}, nil
+}
+
+func TestGenerateValidators(t *testing.T) {
+	attrs := AttributesGenerator{
+		{Name: "name", GenAIValidatorType: "name_validator"},
+		{Name: "spec", NestedAttributes: AttributesGenerator{
+			{Name: "replicas", GenAIValidatorType: "replicas_validator"},
+		}},
+	}
+
+	g := &fakeValidatorGenerator{}
+	validators, err := generateValidators(context.Background(), g, attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"type name_validator struct{}", "type replicas_validator struct{}"}
+	if !reflect.DeepEqual(validators, expected) {
+		t.Errorf("expected %q got %q", expected, validators)
+	}
+
+	g = &fakeValidatorGenerator{err: fmt.Errorf("rate limited")}
+	if _, err := generateValidators(context.Background(), g, attrs); err == nil {
+		t.Errorf("expected an error")
+	}
+	if len(g.calls) != 1 {
+		t.Errorf("expected generation to stop after the first error, got %d calls", len(g.calls))
+	}
+}
+
+func TestOpenAIValidatorGenerator(t *testing.T) {
+	var model string
+	var messages, requests int
+	var content, examples string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected request path %q", r.URL.Path)
+		}
+		var req struct {
+			Model    string           `json:"model"`
+			Messages []map[string]any `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("error decoding request: %v", err)
+		}
+		model, messages = req.Model, len(req.Messages)
+		requests++
+		response := content
+		if req.Messages[0]["content"] == examplesPrompt {
+			response = examples
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			fmt.Fprint(w, `{"error": {"message": "server error", "type": "server_error"}}`)
+			return
+		}
+		choices := []map[string]any{}
+		if response != "" {
+			choices = append(choices, map[string]any{"index": 0, "message": map[string]any{"role": "assistant", "content": response}})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"choices": choices})
+	}))
+	defer server.Close()
+
+	g := NewOpenAIValidatorGenerator(server.URL+"/v1", "test", "local-model")
+
+	content = "```go\ntype name_validator struct{}\n```"
+	examples = "```json\n{\"valid\": [\"abc\"], \"invalid\": [\"\", \"ABC\"]}\n```"
+	v, err := g.GenerateValidator(context.Background(), ValidatorRequest{Type: "name_validator", Interface: "String", Description: "The name."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Code != "type name_validator struct{}" {
+		t.Errorf("expected the code from the response got %q", v.Code)
+	}
+	if len(v.Examples.Valid) != 1 || len(v.Examples.Invalid) != 2 || string(v.Examples.Invalid[1]) != `"ABC"` {
+		t.Errorf("expected the examples from the response got %s and %s", v.Examples.Valid, v.Examples.Invalid)
+	}
+	if model != "local-model" {
+		t.Errorf("expected %q got %q", "local-model", model)
+	}
+
+	requests = 0
+	fix := ValidatorRequest{Type: "name_validator", Interface: "String", Description: "The name.", Code: "type name_validator", Error: "syntax error"}
+	if _, err := g.GenerateValidator(context.Background(), fix); err != nil {
+		t.Fatal(err)
+	}
+	if messages != 4 {
+		t.Errorf("expected the code and errors to be sent when fixing a validator, got %d messages", messages)
+	}
+	if requests != 1 {
+		t.Errorf("expected no examples to be generated when fixing a validator, got %d requests", requests)
+	}
+
+	examples = "the name must be lowercase"
+	v, err = g.GenerateValidator(context.Background(), ValidatorRequest{Type: "name_validator", Interface: "String", Description: "The name."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Code == "" || len(v.Examples.Valid) != 0 || len(v.Examples.Invalid) != 0 {
+		t.Errorf("expected the validator without examples got %q and %v", v.Code, v.Examples)
+	}
+
+	content = ""
+	if _, err := g.GenerateValidator(context.Background(), ValidatorRequest{Type: "name_validator", Description: "The name."}); err == nil || !strings.Contains(err.Error(), "no choices") {
+		t.Errorf("expected an error for a response without choices got %v", err)
+	}
+
+	status = http.StatusInternalServerError
+	if _, err := g.GenerateValidator(context.Background(), ValidatorRequest{Type: "name_validator", Description: "The name."}); err == nil {
+		t.Errorf("expected an error for a failed request")
+	}
+}
+
+func TestGenAIValidatorTypes(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testResourceConfig(t)
+	cfg.Generate.GenAIValidation = true
+	gen := NewResourceGenerator(DefaultProviderConfig(), cfg, spec)
+	backend := &fakeValidatorGenerator{}
+	gen.ValidatorGenerator = backend
+	if err := gen.GenerateGenAIValidators(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	interfaces := map[string]string{}
+	for _, req := range backend.calls {
+		interfaces[req.Type] = req.Interface
+	}
+	for validatorType, expected := range map[string]string{
+		"kubernetes_widget_v1_data_validator":                "Map",
+		"kubernetes_widget_v1_spec_validator":                "Object",
+		"kubernetes_widget_v1_spec_args_validator":           "List",
+		"kubernetes_widget_v1_spec_ports_validator":          "List",
+		"kubernetes_widget_v1_spec_ports_name_validator":     "String",
+		"kubernetes_widget_v1_spec_replicas_validator":       "Int64",
+		"kubernetes_widget_v1_immutable_validator":           "Bool",
+		"kubernetes_widget_v1_spec_ports_protocol_validator": "String",
+	} {
+		if interfaces[validatorType] != expected {
+			t.Errorf("expected %s to implement validator.%s got %q", validatorType, expected, interfaces[validatorType])
+		}
+	}
+
+	code, err := gen.GenerateSchemaFunctionCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"Validators: []validator.Object{\n  kubernetes_widget_v1_spec_validator{},",
+		"Validators: []validator.Map{\n  kubernetes_widget_v1_data_validator{},",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("expected schema to contain %q", expected)
+		}
+	}
+}
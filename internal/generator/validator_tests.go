@@ -0,0 +1,299 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	specresource "github.com/hashicorp/terraform-plugin-codegen-spec/resource"
+)
+
+// maxLiteralLength is the longest example string written as a literal,
+// longer ones use strings.Repeat
+const maxLiteralLength = 16
+
+// patternCandidates are tried in order to find strings that do and don't
+// match a pattern
+var patternCandidates = []string{
+	"a", "abc", "my-name", "my.name", "my_name", "a1", "A", "ABC", "0", "123", "1.5", "-1",
+	"example.com", "10.0.0.1", "user@example.com", "http://example.com", "a/b",
+	"2024-01-01T00:00:00Z", "", " ", "a b", "-", "_", ".", "/", "!",
+}
+
+// testedValidatorTypes are the validator interfaces tests are generated for
+var testedValidatorTypes = []string{BoolValidatorType, StringValidatorType, NumberValidatorType, Int64ValidatorType}
+
+// ValidatorTestCase is a value the generated tests validate
+type ValidatorTestCase struct {
+	Name      string
+	Validator string
+	Value     string
+	Valid     bool
+}
+
+// ValidatorTestsGenerator generates the test function for the validators
+// implementing an interface, e.g. String for validator.String
+type ValidatorTestsGenerator struct {
+	Interface string
+	Cases     []ValidatorTestCase
+}
+
+// GenerateValidatorTests creates test cases for the validators generated from
+// the constraints in the OpenAPI spec, using values that pass and fail them,
+// and for the genAI validators, using the examples generated with them and
+// null and unknown values, which they must accept. Only the genAI validators
+// are tested if the OpenAPI spec could not be read.
+func (g *ResourceGenerator) GenerateValidatorTests(doc *OpenAPIDocument, spec specresource.Resource) {
+	g.ValidatorTests, g.ValidatorTestImports = validatorTests(doc, g.ResourceConfig, spec, g.Schema.Attributes)
+}
+
+func validatorTests(doc *OpenAPIDocument, r ResourceConfig, spec specresource.Resource, attrs AttributesGenerator) ([]ValidatorTestsGenerator, []string) {
+	cases := map[string][]ValidatorTestCase{}
+	imports := map[string]struct{}{}
+
+	walkOpenAPIAttributes(doc, spec.Schema.Attributes, doc.requestSchema(r.OpenAPIConfig.CreatePath), "", func(attr *specresource.Attribute, prop *openAPISchema, specPath string) {
+		attributePath, _, ok := r.schemaPath(specPath)
+		if !ok {
+			return
+		}
+		iface := StringValidatorType
+		if attr.Int64 != nil {
+			iface = Int64ValidatorType
+		}
+		for _, v := range constraintValidators(*attr, prop, specPath) {
+			if len(v.valid) == 0 && len(v.invalid) == 0 {
+				continue
+			}
+			for _, i := range v.validator.Imports {
+				imports[i.Path] = struct{}{}
+			}
+			name := attributePath + " " + validatorFunctionName(v.validator.SchemaDefinition)
+			for _, values := range []struct {
+				values []string
+				valid  bool
+			}{{v.valid, true}, {v.invalid, false}} {
+				for _, value := range values.values {
+					if strings.HasPrefix(value, "strings.") {
+						imports["strings"] = struct{}{}
+					}
+					result := "invalid"
+					if values.valid {
+						result = "valid"
+					}
+					cases[iface] = append(cases[iface], ValidatorTestCase{
+						Name:      name + " " + result + " " + value,
+						Validator: v.validator.SchemaDefinition,
+						Value:     fmt.Sprintf("types.%sValue(%s)", iface, value),
+						Valid:     values.valid,
+					})
+				}
+			}
+		}
+	})
+
+	collectGenAIValidatorTests(cases, imports, attrs)
+
+	tests := []ValidatorTestsGenerator{}
+	for _, iface := range testedValidatorTypes {
+		if len(cases[iface]) > 0 {
+			tests = append(tests, ValidatorTestsGenerator{Interface: iface, Cases: cases[iface]})
+		}
+	}
+	importPaths := []string{}
+	for i := range imports {
+		importPaths = append(importPaths, i)
+	}
+	sort.Strings(importPaths)
+	return tests, importPaths
+}
+
+// collectGenAIValidatorTests adds the examples for the genAI validators, and
+// null and unknown values, which validators should leave to the framework
+func collectGenAIValidatorTests(cases map[string][]ValidatorTestCase, imports map[string]struct{}, attrs AttributesGenerator) {
+	for _, attr := range attrs {
+		collectGenAIValidatorTests(cases, imports, attr.NestedAttributes)
+		if attr.GenAIValidatorType == "" || !hasValidatorTests(attr.ValidatorType) {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, examples := range []struct {
+			values []json.RawMessage
+			valid  bool
+		}{{attr.GenAIValidatorExamples.Valid, true}, {attr.GenAIValidatorExamples.Invalid, false}} {
+			for _, example := range examples.values {
+				value, err := exampleLiteral(attr.ValidatorType, example)
+				if err != nil {
+					slog.Warn("Skipping validator example", "validator", attr.GenAIValidatorType, "example", string(example), "err", err)
+					continue
+				}
+				if seen[value] {
+					continue
+				}
+				seen[value] = true
+				if attr.ValidatorType == NumberValidatorType {
+					imports["math/big"] = struct{}{}
+				}
+				result := "invalid"
+				if examples.valid {
+					result = "valid"
+				}
+				cases[attr.ValidatorType] = append(cases[attr.ValidatorType], ValidatorTestCase{
+					Name:      attr.GenAIValidatorType + " " + result + " " + value,
+					Validator: attr.GenAIValidatorType + "{}",
+					Value:     fmt.Sprintf("types.%sValue(%s)", attr.ValidatorType, value),
+					Valid:     examples.valid,
+				})
+			}
+		}
+		for _, value := range []string{"Null", "Unknown"} {
+			cases[attr.ValidatorType] = append(cases[attr.ValidatorType], ValidatorTestCase{
+				Name:      attr.GenAIValidatorType + " " + strings.ToLower(value),
+				Validator: attr.GenAIValidatorType + "{}",
+				Value:     fmt.Sprintf("types.%s%s()", attr.ValidatorType, value),
+				Valid:     true,
+			})
+		}
+	}
+}
+
+// hasValidatorTests reports whether tests are generated for validators
+// implementing the interface
+func hasValidatorTests(iface string) bool {
+	return slices.Contains(testedValidatorTypes, iface)
+}
+
+// exampleLiteral returns the Go literal for a JSON example value of the
+// validator interface type, e.g. "abc" for a String
+func exampleLiteral(iface string, example json.RawMessage) (string, error) {
+	switch iface {
+	case BoolValidatorType:
+		var v bool
+		if err := json.Unmarshal(example, &v); err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(v), nil
+	case StringValidatorType:
+		var v string
+		if err := json.Unmarshal(example, &v); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%q", v), nil
+	case Int64ValidatorType:
+		var v int64
+		if err := json.Unmarshal(example, &v); err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(v, 10), nil
+	case NumberValidatorType:
+		var v float64
+		if err := json.Unmarshal(example, &v); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("big.NewFloat(%s)", strconv.FormatFloat(v, 'g', -1, 64)), nil
+	}
+	return "", fmt.Errorf("validator.%s is not tested", iface)
+}
+
+// validatorFunctionName returns the function that creates the validator,
+// e.g. OneOf for stringvalidator.OneOf("a", "b")
+func validatorFunctionName(definition string) string {
+	name, _, _ := strings.Cut(definition, "(")
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func stringNotInEnum(enum []any) string {
+	value := "invalid"
+	for containsValue(enum, value) {
+		value += "-value"
+	}
+	return value
+}
+
+func int64NotInEnum(enum []any) int64 {
+	value := int64(0)
+	for _, v := range enum {
+		value = max(value, int64(v.(float64)))
+	}
+	return value + 1
+}
+
+func containsValue(enum []any, value string) bool {
+	for _, v := range enum {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// lengthExamples returns strings with the minimum length and just outside the bounds
+func lengthExamples(lo, hi *int64) ([]string, []string) {
+	valid, invalid := []string{}, []string{}
+	n := int64(0)
+	if lo != nil {
+		n = *lo
+	}
+	valid = append(valid, repeatedString(n))
+	if lo != nil && *lo > 0 {
+		invalid = append(invalid, repeatedString(*lo-1))
+	}
+	if hi != nil {
+		invalid = append(invalid, repeatedString(*hi+1))
+	}
+	return valid, invalid
+}
+
+func repeatedString(n int64) string {
+	if n <= maxLiteralLength {
+		return fmt.Sprintf("%q", strings.Repeat("a", int(n)))
+	}
+	return fmt.Sprintf("strings.Repeat(%q, %d)", "a", n)
+}
+
+// patternExamples returns the first of the enum values and candidates that
+// matches the pattern and the first that does not, if there are any
+func patternExamples(re *regexp.Regexp, enum []any) ([]string, []string) {
+	candidates := []string{}
+	for _, v := range enum {
+		if s, ok := v.(string); ok {
+			candidates = append(candidates, s)
+		}
+	}
+	candidates = append(candidates, patternCandidates...)
+
+	valid, invalid := []string{}, []string{}
+	for _, c := range candidates {
+		if re.MatchString(c) && len(valid) == 0 {
+			valid = append(valid, fmt.Sprintf("%q", c))
+		} else if !re.MatchString(c) && len(invalid) == 0 {
+			invalid = append(invalid, fmt.Sprintf("%q", c))
+		}
+	}
+	return valid, invalid
+}
+
+// rangeExamples returns the lower or upper bound and the values just outside them
+func rangeExamples(lo, hi *int64) ([]string, []string) {
+	valid, invalid := []string{}, []string{}
+	if lo != nil {
+		valid = append(valid, fmt.Sprintf("%d", *lo))
+		if *lo > math.MinInt64 {
+			invalid = append(invalid, fmt.Sprintf("%d", *lo-1))
+		}
+	} else {
+		valid = append(valid, fmt.Sprintf("%d", *hi))
+	}
+	if hi != nil && *hi < math.MaxInt64 {
+		invalid = append(invalid, fmt.Sprintf("%d", *hi+1))
+	}
+	return valid, invalid
+}
@@ -0,0 +1,118 @@
+// Copyright IBM Corp. 2024
+// SPDX-License-Identifier: MPL-2.0
+
+package generator
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestValidatorTests(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := readOpenAPIDocument("testdata/widget_openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testResourceConfig(t)
+	cfg.OpenAPIConfig.CreatePath = "/apis/example.com/v1/namespaces/{namespace}/widgets"
+	attrs := AttributesGenerator{
+		{Name: "name", ValidatorType: StringValidatorType, GenAIValidatorType: "name_validator", GenAIValidatorExamples: ValidatorExamples{
+			Valid:   []json.RawMessage{json.RawMessage(`"abc"`), json.RawMessage(`"abc"`)},
+			Invalid: []json.RawMessage{json.RawMessage(`"ABC"`), json.RawMessage(`5`)},
+		}},
+		{Name: "ratio", ValidatorType: NumberValidatorType, GenAIValidatorType: "ratio_validator", GenAIValidatorExamples: ValidatorExamples{
+			Valid: []json.RawMessage{json.RawMessage(`0.5`)},
+		}},
+		{Name: "labels", ValidatorType: MapValidatorType, GenAIValidatorType: "labels_validator"},
+	}
+
+	tests, imports := validatorTests(doc, cfg, spec, attrs)
+	cases := map[string]ValidatorTestCase{}
+	for _, g := range tests {
+		for _, c := range g.Cases {
+			if _, ok := cases[c.Name]; ok {
+				t.Errorf("expected unique test case names got %q twice", c.Name)
+			}
+			cases[c.Name] = c
+		}
+	}
+	for _, expected := range []ValidatorTestCase{
+		{`spec.restart_policy OneOf valid "Always"`, `stringvalidator.OneOf("Always", "OnFailure", "Never")`, `types.StringValue("Always")`, true},
+		{`spec.restart_policy OneOf invalid "invalid"`, `stringvalidator.OneOf("Always", "OnFailure", "Never")`, `types.StringValue("invalid")`, false},
+		{`metadata.name LengthAtMost invalid strings.Repeat("a", 254)`, `stringvalidator.LengthAtMost(253)`, `types.StringValue(strings.Repeat("a", 254))`, false},
+		{`spec.ports[*].name RegexMatches valid "a"`, "stringvalidator.RegexMatches(regexp.MustCompile(\"^[a-z]([-a-z0-9]*[a-z0-9])?$\"), \"\")", `types.StringValue("a")`, true},
+		{`spec.ports[*].name RegexMatches invalid "my.name"`, "stringvalidator.RegexMatches(regexp.MustCompile(\"^[a-z]([-a-z0-9]*[a-z0-9])?$\"), \"\")", `types.StringValue("my.name")`, false},
+		{"spec.ports[*].container_port Between invalid 0", `int64validator.Between(1, 65535)`, `types.Int64Value(0)`, false},
+		{"spec.ports[*].container_port Between invalid 65536", `int64validator.Between(1, 65535)`, `types.Int64Value(65536)`, false},
+		{"name_validator null", `name_validator{}`, `types.StringNull()`, true},
+		{`name_validator valid "abc"`, `name_validator{}`, `types.StringValue("abc")`, true},
+		{`name_validator invalid "ABC"`, `name_validator{}`, `types.StringValue("ABC")`, false},
+		{"ratio_validator valid big.NewFloat(0.5)", `ratio_validator{}`, `types.NumberValue(big.NewFloat(0.5))`, true},
+	} {
+		found := false
+		for _, g := range tests {
+			for _, c := range g.Cases {
+				found = found || c == expected
+			}
+		}
+		if !found {
+			t.Errorf("expected test case %v", expected)
+		}
+	}
+	if _, ok := cases["labels_validator null"]; ok {
+		t.Errorf("expected no test cases for map validators")
+	}
+	if !sort.StringsAreSorted(imports) {
+		t.Errorf("expected sorted imports got %v", imports)
+	}
+	if _, ok := cases["name_validator invalid 5"]; ok {
+		t.Errorf("expected examples of the wrong type to be skipped")
+	}
+	for _, i := range []string{"math/big", "regexp", "strings", "github.com/hashicorp/terraform-plugin-framework-validators/int64validator"} {
+		if !slices.Contains(imports, i) {
+			t.Errorf("expected imports to contain %q got %v", i, imports)
+		}
+	}
+}
+
+func TestValidatorTestsWithoutOpenAPIDocument(t *testing.T) {
+	spec, err := readResourceSpec("testdata/widget_framework_ir.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testResourceConfig(t)
+	attrs := AttributesGenerator{
+		{Name: "name", ValidatorType: StringValidatorType, GenAIValidatorType: "name_validator"},
+	}
+
+	tests, _ := validatorTests(nil, cfg, spec, attrs)
+	if len(tests) != 1 || len(tests[0].Cases) != 2 {
+		t.Fatalf("expected only the null and unknown tests for the genAI validator got %v", tests)
+	}
+	if rules := GenerateValidationRules(nil, cfg, spec); len(rules) != 0 {
+		t.Errorf("expected no validation rules got %v", rules)
+	}
+}
+
+func TestPatternExamples(t *testing.T) {
+	valid, invalid := patternExamples(regexp.MustCompile(`^[0-9]+$`), nil)
+	if strings.Join(valid, ",") != `"0"` || strings.Join(invalid, ",") != `"a"` {
+		t.Errorf("expected %q and %q got %q and %q", `"0"`, `"a"`, valid, invalid)
+	}
+	valid, _ = patternExamples(regexp.MustCompile(`^x{3}$`), []any{"xxx"})
+	if strings.Join(valid, ",") != `"xxx"` {
+		t.Errorf("expected the enum value to be used, got %q", valid)
+	}
+	valid, _ = patternExamples(regexp.MustCompile(`^[#]{2}$`), nil)
+	if len(valid) != 0 {
+		t.Errorf("expected no valid example got %q", valid)
+	}
+}